@@ -9,9 +9,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"slices"
 	"text/template"
 
 	"go.yaml.in/yaml/v4"
@@ -28,6 +31,27 @@ var (
 	ErrNoSecretRegexp = errors.New("no secret regular expression given")
 )
 
+// Source is the interface of configuration sources provided by the application, e.g. ones fetching the configuration
+// from a remote service. Like the content of files, the content of a source is templated before it is overlaid.
+type Source interface {
+	// Name gives a human-readable identification of the source, used e.g. to record the origin of values.
+	Name() string
+
+	// Open gives a reader for the current content of the source.
+	Open() (io.ReadCloser, error)
+}
+
+// NodeSource is the interface of configuration sources provided by the application that give their content as node
+// structure, e.g. ones built from key-value stores using [NodeFromKeys]. In contrast to [Source], their content is
+// data only and not templated.
+type NodeSource interface {
+	// Name gives a human-readable identification of the source, used e.g. to record the origin of values.
+	Name() string
+
+	// Node gives the current content of the source.
+	Node() (*yaml.Node, error)
+}
+
 // Validator is the interface to facility validity checks on configuration types.
 type Validator interface {
 	// Validate is used to Validate a configuration.
@@ -36,7 +60,27 @@ type Validator interface {
 
 type source struct {
 	fileName string
+	name     string
 	reader   io.Reader
+	external Source
+	nodes    NodeSource
+	profile  string
+	profiled bool
+	priority Priority
+	delims   *delims
+}
+
+// Name gives a human-readable identification of the source, used e.g. to record the origin of values.
+func (s source) Name() string {
+	if s.fileName != "" {
+		return s.fileName
+	}
+
+	if s.name != "" {
+		return s.name
+	}
+
+	return "reader"
 }
 
 func (s source) Reader() (io.ReadCloser, error) {
@@ -44,6 +88,21 @@ func (s source) Reader() (io.ReadCloser, error) {
 		return io.NopCloser(s.reader), nil
 	}
 
+	if s.nodes != nil {
+		// the content is given by the node source itself
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	if s.external != nil {
+		r, err := s.external.Open()
+
+		if err != nil {
+			return nil, fmt.Errorf("could not open %v: %w", s.external.Name(), err)
+		}
+
+		return r, nil
+	}
+
 	if s.fileName != "" {
 		r, err := os.Open(s.fileName)
 
@@ -57,6 +116,19 @@ func (s source) Reader() (io.ReadCloser, error) {
 	return nil, errors.Join(ErrNoConfigPaths, ErrNoConfigReaders)
 }
 
+// withReader gives a copy of the source reading from the given, already opened io.Reader. Profile-related
+// information is left out, as it was already evaluated when opening the source.
+func (s source) withReader(r io.Reader) source {
+	return source{
+		fileName: s.fileName,
+		name:     s.name,
+		reader:   r,
+		nodes:    s.nodes,
+		priority: s.priority,
+		delims:   s.delims,
+	}
+}
+
 // Config is the generic structure holding the configuration information for the specified type.
 type Config[T any] struct {
 	node     *yaml.Node
@@ -64,6 +136,33 @@ type Config[T any] struct {
 	secretRE *regexp.Regexp
 	sources  []source
 	values   map[string]any
+	origins  map[string]Origin
+
+	priority    Priority
+	profiles    []string
+	profileEnvs []string
+	profileArgs []string
+	references  bool
+
+	funcs        template.FuncMap
+	removedFuncs []string
+	sandbox      *Sandbox
+	delims       *delims
+	sourceDelims *delims
+	args         []string
+	argsSet      bool
+	posixFlags   bool
+	envFunc      func(name string) (string, bool)
+	envNames     []string
+
+	workingDirPaths bool
+	exec            *Exec
+	secretResolvers map[string]SecretResolver
+	secretCache     *secretCache
+	texts           sourceTexts
+	http            *HTTP
+	decryptionKey   *decryptionKey
+	secretDetectors []SecretDetector
 }
 
 // configurable defines an interface for managing configuration sources, adding key-value pairs,
@@ -73,6 +172,27 @@ type configurable interface {
 	SetSecretRE(newSecretRE *regexp.Regexp) error
 	addSources(sources ...source) error
 	addValue(key string, val any) error
+	addProfiles(profiles ...string) error
+	addProfileEnv(name string) error
+	addProfileArg(name string) error
+	applyWithPriority(priority Priority, opts ...Option) error
+	enableReferences() error
+	addFuncs(funcs template.FuncMap) error
+	removeFuncs(names ...string) error
+	setSandbox(sandbox Sandbox) error
+	setDelims(left, right string) error
+	applyWithDelims(left, right string, opts ...Option) error
+	setArgs(args []string) error
+	enablePOSIXFlags() error
+	setEnvFunc(lookup func(name string) (string, bool), names []string) error
+	disableSourceRelativePaths() error
+	setExec(execution Exec) error
+	addSecretResolver(scheme string, resolver SecretResolver) error
+	setSecretCache(cache *secretCache) error
+	setSourceTexts(texts sourceTexts) error
+	setHTTP(settings HTTP) error
+	setDecryptionKey(key decryptionKey) error
+	setSecretDetectors(detectors []SecretDetector) error
 }
 
 // Option defines a functional option for configuring a Config instance.
@@ -97,6 +217,18 @@ func (c *Config[T]) addSources(sources ...source) error {
 
 	for i, j := len(c.sources), 0; j < len(sources); i, j = i+1, j+1 {
 		newSources[i] = sources[j]
+
+		if newSources[i].fileName == "" && newSources[i].name == "" {
+			newSources[i].name = fmt.Sprintf("reader[%d]", i)
+		}
+
+		if newSources[i].priority == 0 {
+			newSources[i].priority = c.currentPriority()
+		}
+
+		if newSources[i].delims == nil {
+			newSources[i].delims = c.sourceDelims
+		}
 	}
 
 	c.sources = newSources
@@ -138,6 +270,60 @@ func WithReader(readers ...io.Reader) Option {
 	}
 }
 
+// WithNamedReader creates an Option that adds the provided io.Reader as configuration source with the given name.
+// The name is used to identify the source, e.g. in error messages and origins, instead of a generic one.
+func WithNamedReader(name string, reader io.Reader) Option {
+	return func(c configurable) error {
+		if reader == nil {
+			return ErrNoConfigReaders
+		}
+
+		return c.addSources(source{name: name, reader: reader})
+	}
+}
+
+// WithSource creates an Option that adds the given application-provided sources as configuration sources.
+func WithSource(sources ...Source) Option {
+	return func(c configurable) error {
+		if len(sources) == 0 || slices.Contains(sources, nil) {
+			return ErrNoConfigReaders
+		}
+
+		newSources := make([]source, len(sources))
+
+		for i, s := range sources {
+			newSources[i] = source{name: s.Name(), external: s}
+		}
+
+		return c.addSources(newSources...)
+	}
+}
+
+// WithNodeSource creates an Option that adds the given application-provided node sources as configuration sources.
+func WithNodeSource(sources ...NodeSource) Option {
+	return func(c configurable) error {
+		if len(sources) == 0 || slices.Contains(sources, nil) {
+			return ErrNoConfigReaders
+		}
+
+		newSources := make([]source, len(sources))
+
+		for i, s := range sources {
+			newSources[i] = source{name: s.Name(), nodes: s}
+		}
+
+		return c.addSources(newSources...)
+	}
+}
+
+// withSource creates an Option that adds the given source as is. It is used to hand down sources, including their
+// name, to the configurations created during overlays.
+func withSource(s source) Option {
+	return func(c configurable) error {
+		return c.addSources(s)
+	}
+}
+
 func (c *Config[T]) addValue(key string, value any) error {
 	c.values[key] = value
 
@@ -186,21 +372,35 @@ func (c *Config[T]) Get() *T {
 	return &c.content
 }
 
-// readSources processes all configuration sources, deserializes their content, and
-// validates the resulting configuration.
+// readSources processes all active configuration sources in the order of their priority, deserializes their content,
+// and validates the resulting configuration.
 func (c *Config[T]) readSources() error {
-	if len(c.sources) == 0 {
+	sources := c.activeSources()
+
+	if len(sources) == 0 {
 		return errors.Join(ErrNoConfigPaths, ErrNoConfigReaders)
 	}
 
 	var decodeErr error
 	var validateErr error
 
-	if len(c.sources) == 1 {
+	c.origins = make(map[string]Origin)
+
+	if c.secretCache == nil {
+		c.secretCache = new(secretCache)
+	}
+
+	if c.texts == nil {
+		// the template sources are only kept while reading
+		c.texts = make(sourceTexts)
+		defer func() { c.texts = nil }()
+	}
+
+	if len(sources) == 1 {
 		// to optimize the most common case of a single reader, we do not need to
 		// go over the yaml.Node structure first.
 		decodeErr = func() error {
-			r, err := c.sources[0].Reader()
+			r, err := sources[0].Reader()
 
 			if err != nil {
 				return err
@@ -208,10 +408,10 @@ func (c *Config[T]) readSources() error {
 
 			defer func() { _ = r.Close() }()
 
-			return c.fromSingle(r)
+			return c.fromSingle(r, sources[0].withReader(r))
 		}()
 	} else {
-		for _, v := range c.sources {
+		for _, v := range sources {
 			if err := func() error {
 				r, err := v.Reader()
 
@@ -221,13 +421,23 @@ func (c *Config[T]) readSources() error {
 
 				defer func() { _ = r.Close() }()
 
-				return c.overlay(r)
+				return c.overlay(v.withReader(r))
 			}(); err != nil {
 				return err
 			}
 		}
 
-		decodeErr = c.node.Decode(&c.content)
+		if c.references {
+			decodeErr = resolveReferences(c.node, c.origins)
+		}
+
+		if decodeErr == nil {
+			decodeErr = mergedSourceError(
+				c.origins,
+				c.texts,
+				"",
+				wrapError("could not parse configuration", c.node.Decode(&c.content)))
+		}
 
 		// cleanup
 		c.node = nil
@@ -247,51 +457,145 @@ func (c *Config[T]) readSources() error {
 // fromSingle reads a configuration from the single given io.Reader and
 // runs - if necessary - the contained template functions.
 // It does not retain a node structure needed as a base for merges with other configurations.
-func (c *Config[T]) fromSingle(r io.Reader) error {
+// The given source is used to record the origin of the contained values and to resolve included files.
+func (c *Config[T]) fromSingle(r io.Reader, s source) error {
+	var includeStack []string
+
+	if s.fileName != "" {
+		if absName, absErr := filepath.Abs(s.fileName); absErr == nil {
+			includeStack = []string{absName}
+		}
+	}
+
+	node, origins, err := c.load(r, s, includeStack)
+
+	if err != nil {
+		return err
+	}
+
+	maps.Copy(c.origins, origins)
+
+	if c.references {
+		if refErr := resolveReferences(node, c.origins); refErr != nil {
+			return refErr
+		}
+	}
+
+	if decodeErr := node.Decode(&c.content); decodeErr != nil {
+		return mergedSourceError(c.origins, c.texts, s.Name(), fmt.Errorf("could not parse configuration: %w", decodeErr))
+	}
+
+	return nil
+}
+
+// load reads the content of the given io.Reader, runs the contained template functions and parses the result into a
+// node structure. The reader is the opened content of the given source. Included files are resolved relative to the
+// file name of the source, the include stack contains the absolute names of the files currently being included to
+// detect cycles. Besides the node structure, the origins of all contained values are returned.
+func (c *Config[T]) load(r io.Reader, s source, includeStack []string) (*yaml.Node, map[string]Origin, error) {
+	if s.nodes != nil {
+		return c.loadNodes(s.nodes)
+	}
+
 	fileContent, err := io.ReadAll(r)
 
 	if err != nil {
-		return fmt.Errorf("could not read from reader: %w", err)
+		return nil, nil, fmt.Errorf("could not read from reader: %w", err)
 	}
 
 	var tmpl *template.Template
 
+	left, right := c.delimsOf(s)
+	deadline := c.deadline()
+	decrypted := make(map[string]struct{})
+
 	if tmpl, err = template.
-		New("config").
-		Funcs(templigFunctions()).
+		New(s.Name()).
+		Delims(left, right).
+		Funcs(c.sandboxFunctions(c.templateFunctions(c.baseDir(s), decrypted), deadline)).
 		Parse(string(fileContent)); err != nil {
-		return fmt.Errorf("could not parse template: %w", err)
+		return nil, nil, templateSourceError(s.Name(), fileContent, fmt.Errorf("could not parse template: %w", err))
 	}
 
-	var b bytes.Buffer
+	if sandboxErr := c.sandboxTemplate(tmpl, deadline); sandboxErr != nil {
+		return nil, nil, templateSourceError(s.Name(), fileContent, sandboxErr)
+	}
+
+	b, execErr := c.execute(tmpl, map[string]any{"Values": c.values}, deadline)
 
-	if err = tmpl.Execute(&b, map[string]any{"Values": c.values}); err != nil {
-		return fmt.Errorf("could not execute template: %w", err)
+	if execErr != nil {
+		return nil, nil, templateSourceError(s.Name(), fileContent, execErr)
 	}
 
-	if decodeErr := yaml.NewDecoder(&b).Decode(&c.content); decodeErr != nil {
-		return fmt.Errorf("could not parse configuration: %w", decodeErr)
+	var node yaml.Node
+	rendered := b.Bytes()
+
+	if decodeErr := yaml.NewDecoder(bytes.NewReader(rendered)).Decode(&node); decodeErr != nil {
+		return nil, nil, yamlSourceError(
+			s.Name(), fileContent, rendered, fmt.Errorf("could not parse configuration: %w", decodeErr))
 	}
 
-	return nil
+	c.texts.add(s.Name(), fileContent, rendered)
+
+	origins := make(map[string]Origin)
+	collectOrigins(origins, &node, nil, s.Name())
+	markDecrypted(&node, nil, origins, decrypted)
+
+	if failed, decryptErr := c.secretNodes(&node, nil, origins); decryptErr != nil {
+		return nil, nil, &SourceError{
+			Source:  s.Name(),
+			Line:    failed.Line,
+			Column:  failed.Column,
+			Snippet: sourceSnippet(fileContent, rendered, failed.Line),
+			Err:     decryptErr,
+		}
+	}
+
+	if includeErr := c.resolveIncludes(&node, nil, origins, s, includeStack); includeErr != nil {
+		return nil, nil, includeErr
+	}
+
+	return &node, origins, nil
 }
 
-// overlay is called repeatedly and overlays the current intermediate configuration
-// with the content of the given io.Reader.
-func (c *Config[T]) overlay(r io.Reader) error {
-	opts := make([]Option, 0, len(c.values)+1)
-	opts = append(opts, WithReader(r))
+// loadNodes loads the content of the given node source, recording the origins of its values. Values marked with the
+// `!secret` tag are recorded as secret.
+func (c *Config[T]) loadNodes(s NodeSource) (*yaml.Node, map[string]Origin, error) {
+	node, err := s.Node()
 
-	for k, v := range c.values {
-		opts = append(opts, WithValue(k, v))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not load %v: %w", s.Name(), err)
 	}
 
-	additionalConfig, aErr := New[yaml.Node](opts...)
+	if node == nil {
+		node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+
+	if node.Kind != yaml.DocumentNode {
+		node = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{node}}
+	}
+
+	origins := make(map[string]Origin)
+	collectOrigins(origins, node, nil, s.Name())
+
+	if failed, err := c.secretNodes(node, nil, origins); err != nil {
+		return nil, nil, &SourceError{Source: s.Name(), Line: failed.Line, Column: failed.Column, Err: err}
+	}
+
+	return node, origins, nil
+}
+
+// overlay is called repeatedly and overlays the current intermediate configuration
+// with the content of the given source.
+func (c *Config[T]) overlay(s source) error {
+	additionalConfig, aErr := New[yaml.Node](append(c.inheritedOptions(), withSource(s))...)
 
 	if aErr != nil {
 		return aErr
 	}
 
+	mergeOrigins(c.origins, c.node, additionalConfig.origins)
+
 	if c.node == nil {
 		c.node = additionalConfig.Get()
 	} else {
@@ -307,6 +611,68 @@ func (c *Config[T]) overlay(r io.Reader) error {
 	return nil
 }
 
+// inheritedOptions gives the options to hand down the settings of this instance to the configurations created
+// during overlays.
+func (c *Config[T]) inheritedOptions() []Option {
+	opts := make([]Option, 0, len(c.values)+len(c.secretResolvers)+13)
+
+	for k, v := range c.values {
+		opts = append(opts, WithValue(k, v))
+	}
+
+	if len(c.funcs) > 0 {
+		opts = append(opts, WithFuncs(c.funcs))
+	}
+
+	if len(c.removedFuncs) > 0 {
+		opts = append(opts, WithoutFuncs(c.removedFuncs...))
+	}
+
+	if c.sandbox != nil {
+		opts = append(opts, WithSandbox(*c.sandbox))
+	}
+
+	if c.delims != nil {
+		opts = append(opts, WithDelims(c.delims.left, c.delims.right))
+	}
+
+	if c.argsSet {
+		opts = append(opts, WithArgs(c.args))
+	}
+
+	if c.posixFlags {
+		opts = append(opts, WithPOSIXFlags())
+	}
+
+	if c.envFunc != nil {
+		opts = append(opts, withEnvFunc(c.envFunc, c.envNames))
+	}
+
+	if c.workingDirPaths {
+		opts = append(opts, WithoutSourceRelativePaths())
+	}
+
+	if c.exec != nil {
+		opts = append(opts, WithExec(*c.exec))
+	}
+
+	for scheme, resolver := range c.secretResolvers {
+		opts = append(opts, WithSecretResolver(scheme, resolver))
+	}
+
+	if c.http != nil {
+		opts = append(opts, WithHTTP(*c.http))
+	}
+
+	if c.decryptionKey != nil {
+		opts = append(opts, withDecryptionKey(*c.decryptionKey))
+	}
+
+	opts = append(opts, withSecretCache(c.secretCache), withSourceTexts(c.texts))
+
+	return opts
+}
+
 // Validate checks if the configuration is valid if the content fulfills the Validator interface.
 func (c *Config[T]) Validate() error {
 	if v, ok := any(&c.content).(Validator); ok {
@@ -327,6 +693,39 @@ func (c *Config[T]) To(w io.Writer) error {
 	return errors.Join(err, encCloseErr)
 }
 
+// ToAnnotated writes the configuration to the given io.Writer, annotating each value with a comment naming the
+// source and line it was last set from, e.g.
+//
+//	url: https://prod.example.com # from prod.yaml:12
+//
+// Values that do not originate from a source, e.g. defaults set by the configuration type, are not annotated.
+func (c *Config[T]) ToAnnotated(w io.Writer) error {
+	var writeErr error
+	var encCloseErr error
+	node := yaml.Node{}
+
+	encodeErr := node.Encode(c.content)
+
+	if encodeErr == nil {
+		annotateOrigins(&node, nil, c.origins)
+
+		enc := yaml.NewEncoder(w)
+		writeErr = enc.Encode(&node)
+		encCloseErr = enc.Close()
+	}
+
+	return errors.Join(encodeErr, writeErr, encCloseErr)
+}
+
+// Origin gives the origin of the value at the given path. The path consists of the mapping keys and sequence
+// indices leading to the value, separated by dots, e.g. `database.hosts.0`. The second return value indicates
+// whether an origin is known for that path.
+func (c *Config[T]) Origin(path string) (Origin, bool) {
+	o, ok := c.origins[path]
+
+	return o, ok
+}
+
 // ToFile saves a configuration to a file with the given name, replacing it in case.
 func (c *Config[T]) ToFile(path string) error {
 	f, err := os.Create(filepath.Clean(path))
@@ -355,6 +754,8 @@ func (c *Config[T]) ToFile(path string) error {
 //
 //	id: id0
 //	secrets: *
+//
+// Values that were decrypted and values of fields tagged `templig:"secret"` are hidden regardless of their key.
 func (c *Config[T]) ToSecretsHidden(w io.Writer) error {
 	var writeErr error
 	var encCloseErr error
@@ -363,7 +764,9 @@ func (c *Config[T]) ToSecretsHidden(w io.Writer) error {
 	encodeErr := node.Encode(c.content)
 
 	if encodeErr == nil {
-		HideSecrets(&node, true, c.secretRE)
+		c.hideDecrypted(&node, nil)
+		hideTaggedSecrets(&node, reflect.TypeFor[T](), true)
+		HideSecrets(&node, true, c.secretRE, c.secretDetectors...)
 
 		enc := yaml.NewEncoder(w)
 		writeErr = enc.Encode(node)
@@ -390,6 +793,8 @@ func (c *Config[T]) ToSecretsHidden(w io.Writer) error {
 //	secrets:
 //	  - *******
 //	  - *******
+//
+// Values that were decrypted and values of fields tagged `templig:"secret"` are hidden regardless of their key.
 func (c *Config[T]) ToSecretsHiddenStructured(w io.Writer) error {
 	var writeErr error
 	var encCloseErr error
@@ -398,7 +803,9 @@ func (c *Config[T]) ToSecretsHiddenStructured(w io.Writer) error {
 	encodeErr := node.Encode(c.content)
 
 	if encodeErr == nil {
-		HideSecrets(&node, false, c.secretRE)
+		c.hideDecrypted(&node, nil)
+		hideTaggedSecrets(&node, reflect.TypeFor[T](), false)
+		HideSecrets(&node, false, c.secretRE, c.secretDetectors...)
 
 		enc := yaml.NewEncoder(w)
 		writeErr = enc.Encode(node)
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"slices"
@@ -604,6 +605,62 @@ func TestNoSources(t *testing.T) {
 	}
 }
 
+type CustomSource struct {
+	name    string
+	content string
+	err     error
+}
+
+func (s CustomSource) Name() string {
+	return s.name
+}
+
+func (s CustomSource) Open() (io.ReadCloser, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return io.NopCloser(strings.NewReader(s.content)), nil
+}
+
+func TestSource(t *testing.T) {
+	t.Parallel()
+
+	c, err := templig.New[TestConfig](
+		templig.WithFile("testData/test_config_0.yaml"),
+		templig.WithSource(CustomSource{name: "custom", content: `name: {{ "custom" | upper }}`}))
+
+	if err != nil {
+		t.Fatalf("did not want error but got %v", err)
+	}
+
+	if c.Get().Name != "CUSTOM" {
+		t.Errorf("wanted name CUSTOM but got %v", c.Get().Name)
+	}
+
+	if origin, _ := c.Origin("name"); origin.Source != "custom" {
+		t.Errorf("wanted origin custom but got %v", origin)
+	}
+}
+
+func TestBrokenSource(t *testing.T) {
+	t.Parallel()
+
+	_, err := templig.New[TestConfig](
+		templig.WithFile("testData/test_config_0.yaml"),
+		templig.WithSource(CustomSource{name: "broken", err: io.ErrUnexpectedEOF}))
+
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("reading from broken source should have returned an error")
+	}
+
+	_, err = templig.New[TestConfig](templig.WithSource(nil))
+
+	if !errors.Is(err, templig.ErrNoConfigReaders) {
+		t.Errorf("giving no source should have returned an error")
+	}
+}
+
 func TestBrokenWriter(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig
+
+import (
+	"errors"
+)
+
+// ErrInvalidDelims indicates that template delimiters were empty.
+var ErrInvalidDelims = errors.New("template delimiters must not be empty")
+
+// delims holds the left and right template action delimiters.
+type delims struct {
+	left  string
+	right string
+}
+
+// WithDelims creates an Option that sets the template action delimiters of the configuration, e.g. `[[` and `]]`
+// instead of the default `{{` and `}}`. This allows literal `{{ }}` in values, e.g. for embedded Go templates.
+func WithDelims(left, right string) Option {
+	return func(c configurable) error {
+		return c.setDelims(left, right)
+	}
+}
+
+// WithSourceDelims creates an Option that sets the template action delimiters of all sources added by the given
+// options, overriding the ones set using [WithDelims] for these sources.
+//
+//	templig.New[Config](
+//	    templig.WithFile("my_config.yaml"),
+//	    templig.WithSourceDelims("[[", "]]", templig.WithFile("alerts.yaml")),
+//	)
+func WithSourceDelims(left, right string, opts ...Option) Option {
+	return func(c configurable) error {
+		return c.applyWithDelims(left, right, opts...)
+	}
+}
+
+func (c *Config[T]) setDelims(left, right string) error {
+	if left == "" || right == "" {
+		return ErrInvalidDelims
+	}
+
+	c.delims = &delims{left: left, right: right}
+
+	return nil
+}
+
+func (c *Config[T]) applyWithDelims(left, right string, opts ...Option) error {
+	if left == "" || right == "" {
+		return ErrInvalidDelims
+	}
+
+	oldDelims := c.sourceDelims
+	c.sourceDelims = &delims{left: left, right: right}
+
+	defer func() { c.sourceDelims = oldDelims }()
+
+	var errs []error
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// delimsOf gives the template action delimiters to use for the given source. Empty delimiters select the default
+// ones of the templating engine.
+func (c *Config[T]) delimsOf(s source) (string, string) {
+	switch {
+	case s.delims != nil:
+		return s.delims.left, s.delims.right
+	case c.delims != nil:
+		return c.delims.left, c.delims.right
+	default:
+		return "", ""
+	}
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AlphaOne1/templig"
+)
+
+func TestDelims(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		options  func() []templig.Option
+		wantName string
+		wantURL  string
+		wantErr  error
+	}{
+		{ // 0
+			options: func() []templig.Option {
+				return []templig.Option{
+					templig.WithDelims("[[", "]]"),
+					templig.WithReader(strings.NewReader(`name: "[[ "name0" | upper ]] {{ .Labels }}"`)),
+				}
+			},
+			wantName: "NAME0 {{ .Labels }}",
+		},
+		{ // 1
+			options: func() []templig.Option {
+				return []templig.Option{
+					templig.WithDelims("[[", "]]"),
+					templig.WithReader(strings.NewReader(`id: 9`)),
+					templig.WithReader(strings.NewReader(`name: "[[ "name0" | upper ]] {{ .Labels }}"`)),
+				}
+			},
+			wantName: "NAME0 {{ .Labels }}",
+		},
+		{ // 2
+			options: func() []templig.Option {
+				return []templig.Option{
+					templig.WithReader(strings.NewReader(`name: {{ "name0" | upper }}`)),
+					templig.WithSourceDelims("<%", "%>",
+						templig.WithReader(strings.NewReader(`conn: {url: "<% "x" %>{{ .Labels }}"}`))),
+				}
+			},
+			wantName: "NAME0",
+			wantURL:  "x{{ .Labels }}",
+		},
+		{ // 3
+			options: func() []templig.Option {
+				return []templig.Option{
+					templig.WithDelims("[[", "]]"),
+					templig.WithSourceDelims("<%", "%>",
+						templig.WithReader(strings.NewReader(`name: "<% "name0" %>[[ x ]]"`))),
+				}
+			},
+			wantName: "name0[[ x ]]",
+		},
+		{ // 4
+			options: func() []templig.Option {
+				return []templig.Option{
+					templig.WithDelims("[[", ""),
+					templig.WithReader(strings.NewReader(`name: name0`)),
+				}
+			},
+			wantErr: templig.ErrInvalidDelims,
+		},
+		{ // 5
+			options: func() []templig.Option {
+				return []templig.Option{
+					templig.WithSourceDelims("", "]]", templig.WithReader(strings.NewReader(`name: name0`))),
+				}
+			},
+			wantErr: templig.ErrInvalidDelims,
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("Delims-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			config, configErr := templig.New[TestConfig](test.options()...)
+
+			if test.wantErr != nil {
+				if !errors.Is(configErr, test.wantErr) {
+					t.Errorf("%v: wanted error %v but got %v", testIndex, test.wantErr, configErr)
+				}
+
+				return
+			}
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			if config.Get().Name != test.wantName {
+				t.Errorf("%v: wanted name %v but got %v", testIndex, test.wantName, config.Get().Name)
+			}
+
+			if test.wantURL != "" && (config.Get().Conn == nil || config.Get().Conn.URL != test.wantURL) {
+				t.Errorf("%v: wanted url %v but got %v", testIndex, test.wantURL, config.Get().Conn)
+			}
+		})
+	}
+}
@@ -0,0 +1,388 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v4"
+)
+
+// EncryptedTag is the YAML tag marking values to be decrypted while loading the configuration.
+const EncryptedTag = "!encrypted"
+
+// SecretTag is the YAML tag marking values as secret, so they are hidden by [Config.ToSecretsHidden] regardless of
+// their key. Besides that, they are resolved like untagged values. It is intended to be used by sources decrypting
+// their values, but can be used in any configuration.
+const SecretTag = "!secret"
+
+// KeySize is the size in bytes of the keys used to encrypt values.
+const KeySize = 32
+
+// encryptedPrefix identifies the format of encrypted values, AES-256 in Galois/Counter Mode.
+const encryptedPrefix = "AES256-GCM:"
+
+var (
+	// ErrInvalidKey indicates that a key for encrypted values does not have [KeySize] bytes.
+	ErrInvalidKey = errors.New("invalid encryption key")
+
+	// ErrNoDecryptionKey indicates that an encrypted value was found, but no key to decrypt it was given.
+	ErrNoDecryptionKey = errors.New("no decryption key given")
+
+	// ErrDecryption indicates that a value could not be decrypted, e.g. as it was encrypted using another key.
+	ErrDecryption = errors.New("could not decrypt value")
+)
+
+// GenerateKey generates a new random key to encrypt values. To be given in environment variables or files, it has to
+// be encoded using standard base64 encoding.
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("could not generate key: %w", err)
+	}
+
+	return key, nil
+}
+
+// Encrypt encrypts the given value using the given key, so it can be used in configurations with the `!encrypted`
+// tag or the `decrypt` template function.
+func Encrypt(value string, key []byte) (string, error) {
+	aead, err := newAEAD(key)
+
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("could not generate nonce: %w", err)
+	}
+
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(value), nil)), nil
+}
+
+// decrypt decrypts the given value, encrypted using [Encrypt], using the given key.
+func decrypt(value string, key []byte) (string, error) {
+	aead, err := newAEAD(key)
+
+	if err != nil {
+		return "", err
+	}
+
+	encoded, found := strings.CutPrefix(strings.TrimSpace(value), encryptedPrefix)
+
+	if !found {
+		return "", fmt.Errorf("%w: unknown format", ErrDecryption)
+	}
+
+	data, decodeErr := base64.StdEncoding.DecodeString(encoded)
+
+	if decodeErr != nil || len(data) < aead.NonceSize() {
+		return "", fmt.Errorf("%w: invalid encoding", ErrDecryption)
+	}
+
+	plain, openErr := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+
+	if openErr != nil {
+		return "", fmt.Errorf("%w: %w", ErrDecryption, openErr)
+	}
+
+	return string(plain), nil
+}
+
+// newAEAD creates the AES-256-GCM cipher for the given key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("%w: wanted %d bytes but got %d", ErrInvalidKey, KeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+
+	return aead, nil
+}
+
+// decryptionKey describes where the key to decrypt values is taken from.
+type decryptionKey struct {
+	key  []byte
+	env  string
+	file string
+}
+
+// WithDecryptionKey creates an Option that sets the key to decrypt values marked with the `!encrypted` tag and
+// given to the `decrypt` template function.
+func WithDecryptionKey(key []byte) Option {
+	return func(c configurable) error {
+		if len(key) != KeySize {
+			return fmt.Errorf("%w: wanted %d bytes but got %d", ErrInvalidKey, KeySize, len(key))
+		}
+
+		return c.setDecryptionKey(decryptionKey{key: slices.Clone(key)})
+	}
+}
+
+// WithDecryptionKeyFromEnv creates an Option that takes the key to decrypt values from the environment variable with
+// the given name, encoded using standard base64 encoding.
+func WithDecryptionKeyFromEnv(name string) Option {
+	return func(c configurable) error {
+		return c.setDecryptionKey(decryptionKey{env: name})
+	}
+}
+
+// WithDecryptionKeyFile creates an Option that takes the key to decrypt values from the file with the given name,
+// encoded using standard base64 encoding.
+func WithDecryptionKeyFile(fileName string) Option {
+	return func(c configurable) error {
+		return c.setDecryptionKey(decryptionKey{file: fileName})
+	}
+}
+
+// withDecryptionKey creates an Option that sets where the key to decrypt values is taken from. It is used to hand the
+// key down to the configurations created during overlays.
+func withDecryptionKey(key decryptionKey) Option {
+	return func(c configurable) error {
+		return c.setDecryptionKey(key)
+	}
+}
+
+func (c *Config[T]) setDecryptionKey(key decryptionKey) error {
+	c.decryptionKey = &key
+
+	return nil
+}
+
+// key gives the key to decrypt values, reading it from the environment or a file if needed.
+func (c *Config[T]) key() ([]byte, error) {
+	var encoded string
+
+	switch {
+	case c.decryptionKey == nil:
+		return nil, ErrNoDecryptionKey
+	case c.decryptionKey.key != nil:
+		return c.decryptionKey.key, nil
+	case c.decryptionKey.env != "":
+		encoded = c.getEnv(c.decryptionKey.env)
+
+		if encoded == "" {
+			return nil, fmt.Errorf("%w: environment variable %v not set", ErrNoDecryptionKey, c.decryptionKey.env)
+		}
+	default:
+		content, err := os.ReadFile(filepath.Clean(c.decryptionKey.file))
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrNoDecryptionKey, err)
+		}
+
+		encoded = string(content)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+
+	return key, nil
+}
+
+// decryptValue gives the plain text of the given encrypted value, using the key of the instance.
+func (c *Config[T]) decryptValue(value string) (string, error) {
+	key, err := c.key()
+
+	if err != nil {
+		return "", err
+	}
+
+	return decrypt(value, key)
+}
+
+// decryptFunction gives the `decrypt` template function, giving the plain text of the given encrypted value. The
+// plain texts are recorded in decrypted, so the values containing them can be marked as secret using markDecrypted.
+func (c *Config[T]) decryptFunction(decrypted map[string]struct{}) func(value string) (string, error) {
+	return func(value string) (string, error) {
+		plain, err := c.decryptValue(value)
+
+		if err == nil && plain != "" {
+			decrypted[plain] = struct{}{}
+		}
+
+		return plain, err
+	}
+}
+
+// markDecrypted marks the origins of all scalar values of the given node structure as secret, that contain one of
+// the given plain texts produced by the `decrypt` template function.
+func markDecrypted(node *yaml.Node, path []string, origins map[string]Origin, decrypted map[string]struct{}) {
+	if len(decrypted) == 0 {
+		return
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, v := range node.Content {
+			markDecrypted(v, path, origins, decrypted)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			markDecrypted(node.Content[i+1], append(path, node.Content[i].Value), origins, decrypted)
+		}
+	case yaml.SequenceNode:
+		for i, v := range node.Content {
+			markDecrypted(v, append(path, strconv.Itoa(i)), origins, decrypted)
+		}
+	case yaml.ScalarNode:
+		for plain := range decrypted {
+			if strings.Contains(node.Value, plain) {
+				markSecret(node, path, origins)
+
+				break
+			}
+		}
+	default:
+		// aliases are handled at their anchors
+	}
+}
+
+// secretNodes decrypts all scalar values marked with the `!encrypted` tag in the given node structure and removes
+// the `!secret` tag. The origins of both are marked as secret, for mappings and sequences marked with `!secret` the
+// origins of all their values. If a value cannot be decrypted, its node is returned alongside the error.
+func (c *Config[T]) secretNodes(node *yaml.Node, path []string, origins map[string]Origin) (*yaml.Node, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, v := range node.Content {
+			if failed, err := c.secretNodes(v, path, origins); err != nil {
+				return failed, err
+			}
+		}
+	case yaml.MappingNode, yaml.SequenceNode:
+		return c.secretCollection(node, path, origins)
+	case yaml.ScalarNode:
+		switch node.Tag {
+		case EncryptedTag:
+			plain, err := c.decryptValue(node.Value)
+
+			if err != nil {
+				return node, err
+			}
+
+			// the plain text is resolved like an untagged value, so it can be of any scalar type
+			node.Value = plain
+			node.Style = 0
+		case SecretTag:
+			// the value is kept as is, only its origin is marked
+		default:
+			return nil, nil
+		}
+
+		node.Tag = ""
+
+		markSecret(node, path, origins)
+	default:
+		// aliases are handled at their anchors
+	}
+
+	return nil, nil
+}
+
+// secretCollection handles the values of the given mapping or sequence like secretNodes. If the collection itself is
+// marked with `!secret`, the tag is removed and the origins of all its values are marked as secret. Collections
+// cannot be encrypted as a whole, so the `!encrypted` tag is reported as error.
+func (c *Config[T]) secretCollection(node *yaml.Node, path []string, origins map[string]Origin) (*yaml.Node, error) {
+	switch node.Tag {
+	case EncryptedTag:
+		return node, fmt.Errorf("%w: only scalar values can be encrypted", ErrDecryption)
+	case SecretTag:
+		node.Tag = ""
+
+		defer markSecret(node, path, origins)
+	default:
+		// not marked as a whole
+	}
+
+	if node.Kind == yaml.SequenceNode {
+		for i, v := range node.Content {
+			if failed, err := c.secretNodes(v, append(path, strconv.Itoa(i)), origins); err != nil {
+				return failed, err
+			}
+		}
+
+		return nil, nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if failed, err := c.secretNodes(node.Content[i+1], append(path, node.Content[i].Value), origins); err != nil {
+			return failed, err
+		}
+	}
+
+	return nil, nil
+}
+
+// markSecret marks the origins of all scalar values of the given node structure as secret.
+func markSecret(node *yaml.Node, path []string, origins map[string]Origin) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			markSecret(node.Content[i+1], append(path, node.Content[i].Value), origins)
+		}
+	case yaml.SequenceNode:
+		for i, v := range node.Content {
+			markSecret(v, append(path, strconv.Itoa(i)), origins)
+		}
+	case yaml.ScalarNode:
+		key := strings.Join(path, ".")
+		origin := origins[key]
+		origin.Secret = true
+		origins[key] = origin
+	default:
+		// aliases are handled at their anchors
+	}
+}
+
+// hideDecrypted hides all scalar values of the given node structure that were decrypted or marked as secret,
+// regardless of their key. They are identified by their recorded origin, references to them are marked as secret
+// while resolving the references.
+func (c *Config[T]) hideDecrypted(node *yaml.Node, path []string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, v := range node.Content {
+			c.hideDecrypted(v, path)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			c.hideDecrypted(node.Content[i+1], append(path, node.Content[i].Value))
+		}
+	case yaml.SequenceNode:
+		for i, v := range node.Content {
+			c.hideDecrypted(v, append(path, strconv.Itoa(i)))
+		}
+	case yaml.ScalarNode:
+		if c.origins[strings.Join(path, ".")].Secret {
+			hideAll(node, false)
+		}
+	default:
+		// aliases are hidden at their anchors
+	}
+}
@@ -0,0 +1,392 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AlphaOne1/templig"
+)
+
+func TestEncrypted(t *testing.T) {
+	t.Parallel()
+
+	key, keyErr := templig.GenerateKey()
+	otherKey, otherKeyErr := templig.GenerateKey()
+
+	if err := errors.Join(keyErr, otherKeyErr); err != nil {
+		t.Fatalf("could not generate keys: %v", err)
+	}
+
+	name, nameErr := templig.Encrypt("secretName", key)
+	id, idErr := templig.Encrypt("42", key)
+
+	if err := errors.Join(nameErr, idErr); err != nil {
+		t.Fatalf("could not encrypt: %v", err)
+	}
+
+	encodedKey := base64.StdEncoding.EncodeToString(key)
+	keyFile := filepath.Join(t.TempDir(), "key")
+
+	if err := os.WriteFile(keyFile, []byte(encodedKey+"\n"), 0o600); err != nil {
+		t.Fatalf("could not write key file: %v", err)
+	}
+
+	tests := []struct {
+		in        []string
+		options   []templig.Option
+		wantID    int
+		wantName  string
+		wantErrIs error
+	}{
+		{ // 0
+			in:       []string{"id: !encrypted " + id + "\nname: !encrypted " + name},
+			options:  []templig.Option{templig.WithDecryptionKey(key)},
+			wantID:   42,
+			wantName: "secretName",
+		},
+		{ // 1
+			in:       []string{`name: {{ decrypt "` + name + `" }}`},
+			options:  []templig.Option{templig.WithDecryptionKey(key)},
+			wantName: "secretName",
+		},
+		{ // 2
+			in: []string{"id: 9", "name: !encrypted " + name},
+			options: []templig.Option{
+				templig.WithDecryptionKeyFromEnv("TEMPLIG_TEST_KEY"),
+				templig.WithEnv(map[string]string{"TEMPLIG_TEST_KEY": encodedKey}),
+			},
+			wantID:   9,
+			wantName: "secretName",
+		},
+		{ // 3
+			in:       []string{"name: !encrypted " + name},
+			options:  []templig.Option{templig.WithDecryptionKeyFile(keyFile)},
+			wantName: "secretName",
+		},
+		{ // 4
+			in:        []string{"name: !encrypted " + name},
+			wantErrIs: templig.ErrNoDecryptionKey,
+		},
+		{ // 5
+			in:        []string{"name: !encrypted " + name},
+			options:   []templig.Option{templig.WithDecryptionKey(otherKey)},
+			wantErrIs: templig.ErrDecryption,
+		},
+		{ // 6
+			in:        []string{"name: !encrypted plain"},
+			options:   []templig.Option{templig.WithDecryptionKey(key)},
+			wantErrIs: templig.ErrDecryption,
+		},
+		{ // 7
+			in:        []string{"name: !encrypted " + name},
+			options:   []templig.Option{templig.WithDecryptionKey(key[:16])},
+			wantErrIs: templig.ErrInvalidKey,
+		},
+		{ // 8
+			in: []string{"name: !encrypted " + name},
+			options: []templig.Option{
+				templig.WithDecryptionKeyFromEnv("TEMPLIG_TEST_KEY"),
+				templig.WithEnv(map[string]string{}),
+			},
+			wantErrIs: templig.ErrNoDecryptionKey,
+		},
+		{ // 9
+			in: []string{"name: !encrypted " + name},
+			options: []templig.Option{
+				templig.WithDecryptionKeyFromEnv("TEMPLIG_TEST_KEY"),
+				templig.WithEnv(map[string]string{"TEMPLIG_TEST_KEY": "no base64"}),
+			},
+			wantErrIs: templig.ErrInvalidKey,
+		},
+		{ // 10
+			in:        []string{"name: !encrypted " + name},
+			options:   []templig.Option{templig.WithDecryptionKeyFile("testData/does_not_exist.key")},
+			wantErrIs: templig.ErrNoDecryptionKey,
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("Encrypted-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			options := test.options
+
+			for _, v := range test.in {
+				options = append(options, templig.WithReader(strings.NewReader(v)))
+			}
+
+			config, configErr := templig.New[TestConfig](options...)
+
+			if test.wantErrIs != nil {
+				if !errors.Is(configErr, test.wantErrIs) {
+					t.Errorf("%v: wanted error %v but got %v", testIndex, test.wantErrIs, configErr)
+				}
+
+				return
+			}
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			if config.Get().ID != test.wantID || config.Get().Name != test.wantName {
+				t.Errorf("%v: wanted %v/%v but got %v/%v",
+					testIndex, test.wantID, test.wantName, config.Get().ID, config.Get().Name)
+			}
+		})
+	}
+}
+
+func TestEncryptedPosition(t *testing.T) {
+	t.Parallel()
+
+	key, keyErr := templig.GenerateKey()
+
+	if keyErr != nil {
+		t.Fatalf("could not generate key: %v", keyErr)
+	}
+
+	_, err := templig.New[TestConfig](
+		templig.WithDecryptionKey(key),
+		templig.WithNamedReader("encrypted", strings.NewReader("id: 9\nname: !encrypted AES256-GCM:AAAA")))
+
+	srcErr := (*templig.SourceError)(nil)
+
+	if !errors.As(err, &srcErr) {
+		t.Fatalf("wanted source error but got %v", err)
+	}
+
+	if srcErr.Source != "encrypted" || srcErr.Line != 2 || srcErr.Column != 7 {
+		t.Errorf("wanted error at encrypted:2:7 but got %v:%v:%v", srcErr.Source, srcErr.Line, srcErr.Column)
+	}
+}
+
+func TestEncryptedHidden(t *testing.T) {
+	t.Parallel()
+
+	key, keyErr := templig.GenerateKey()
+
+	if keyErr != nil {
+		t.Fatalf("could not generate key: %v", keyErr)
+	}
+
+	name, nameErr := templig.Encrypt("secretName", key)
+	host, hostErr := templig.Encrypt("db.example.com", key)
+
+	if err := errors.Join(nameErr, hostErr); err != nil {
+		t.Fatalf("could not encrypt: %v", err)
+	}
+
+	config, configErr := templig.New[TestConfig](
+		templig.WithDecryptionKey(key),
+		templig.WithReader(strings.NewReader("id: 9\nname: !encrypted "+name)),
+		templig.WithReader(strings.NewReader(`conn: { url: {{ decrypt "`+host+`" }} }`)))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	for _, hide := range []func(*templig.Config[TestConfig], *bytes.Buffer) error{
+		func(c *templig.Config[TestConfig], b *bytes.Buffer) error { return c.ToSecretsHidden(b) },
+		func(c *templig.Config[TestConfig], b *bytes.Buffer) error { return c.ToSecretsHiddenStructured(b) },
+	} {
+		var b bytes.Buffer
+
+		if err := hide(config, &b); err != nil {
+			t.Fatalf("did not want error but got %v", err)
+		}
+
+		if strings.Contains(b.String(), "secretName") || strings.Contains(b.String(), "db.example.com") {
+			t.Errorf("wanted decrypted values hidden but got %v", b.String())
+		}
+
+		if !strings.Contains(b.String(), "id: 9") {
+			t.Errorf("wanted other values visible but got %v", b.String())
+		}
+	}
+
+	if origin, found := config.Origin("name"); !found || !origin.Secret {
+		t.Errorf("wanted origin of name to be secret but got %v", origin)
+	}
+}
+
+func TestEncryptedHiddenByOrigin(t *testing.T) {
+	t.Parallel()
+
+	key, keyErr := templig.GenerateKey()
+
+	if keyErr != nil {
+		t.Fatalf("could not generate key: %v", keyErr)
+	}
+
+	owner, ownerErr := templig.Encrypt("admin", key)
+	host, hostErr := templig.Encrypt("db.example.com", key)
+
+	if err := errors.Join(ownerErr, hostErr); err != nil {
+		t.Fatalf("could not encrypt: %v", err)
+	}
+
+	config, configErr := templig.New[map[string]any](
+		templig.WithDecryptionKey(key),
+		templig.WithReferences(),
+		templig.WithReader(strings.NewReader(
+			"db:\n  user: admin\n  port: 5432\n  pin: !secret 5432\n  owner: !encrypted "+owner+"\n"+
+				"  copy: ${db.owner}\n  url: postgres://${db.owner}@host\nbackup: ${db}")),
+		templig.WithReader(strings.NewReader(`remote: { host: {{ decrypt "`+host+`" }} }`)),
+		templig.WithReader(strings.NewReader(`local: { host: db.example.com }`)))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	var b bytes.Buffer
+
+	if err := config.ToSecretsHidden(&b); err != nil {
+		t.Fatalf("did not want error but got %v", err)
+	}
+
+	// equal values of other keys stay visible, references to secrets are hidden
+	want := `backup:
+    copy: '*****'
+    owner: '*****'
+    pin: '****'
+    port: 5432
+    url: '*********************'
+    user: admin
+db:
+    copy: '*****'
+    owner: '*****'
+    pin: '****'
+    port: 5432
+    url: '*********************'
+    user: admin
+local:
+    host: db.example.com
+remote:
+    host: '**************'
+`
+
+	if b.String() != want {
+		t.Errorf("wanted\n%v\nbut got\n%v", want, b.String())
+	}
+}
+
+func TestEncrypt(t *testing.T) {
+	t.Parallel()
+
+	if _, err := templig.Encrypt("value", []byte("short")); !errors.Is(err, templig.ErrInvalidKey) {
+		t.Errorf("wanted error %v but got %v", templig.ErrInvalidKey, err)
+	}
+
+	key, keyErr := templig.GenerateKey()
+
+	if keyErr != nil {
+		t.Fatalf("could not generate key: %v", keyErr)
+	}
+
+	first, firstErr := templig.Encrypt("value", key)
+	second, secondErr := templig.Encrypt("value", key)
+
+	if err := errors.Join(firstErr, secondErr); err != nil {
+		t.Fatalf("could not encrypt: %v", err)
+	}
+
+	if first == second {
+		t.Errorf("wanted different encryptions of the same value but got %v twice", first)
+	}
+}
+
+func TestSecretTag(t *testing.T) {
+	t.Parallel()
+
+	config, configErr := templig.New[map[string]any](
+		templig.WithReader(strings.NewReader("id: !secret 9\nname: !secret \"0042\"\nhost: db")))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	if (*config.Get())["id"] != 9 || (*config.Get())["name"] != "0042" {
+		t.Errorf("wanted secret values resolved like untagged ones but got %v", *config.Get())
+	}
+
+	var b bytes.Buffer
+
+	if err := config.ToSecretsHidden(&b); err != nil {
+		t.Fatalf("did not want error but got %v", err)
+	}
+
+	if strings.Contains(b.String(), "9") || strings.Contains(b.String(), "0042") || !strings.Contains(b.String(), "db") {
+		t.Errorf("wanted secret values hidden but got %v", b.String())
+	}
+}
+
+func TestSecretTagCollections(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr error
+	}{
+		{ // 0
+			in:   "db: !secret {dsn: x, port: 5432}\nhost: h",
+			want: "db:\n    dsn: '*'\n    port: '****'\nhost: h\n",
+		},
+		{ // 1
+			in:   "list: !secret [a, b]\nhost: h",
+			want: "host: h\nlist:\n    - '*'\n    - '*'\n",
+		},
+		{ // 2
+			in:   "db: !secret {users: [a, {name: b}]}\nhost: h",
+			want: "db:\n    users:\n        - '*'\n        - name: '*'\nhost: h\n",
+		},
+		{ // 3
+			in:      "db: !encrypted {dsn: x}",
+			wantErr: templig.ErrDecryption,
+		},
+		{ // 4
+			in:      "list: !encrypted [a, b]",
+			wantErr: templig.ErrDecryption,
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("SecretTagCollections-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			config, configErr := templig.New[map[string]any](templig.WithReader(strings.NewReader(test.in)))
+
+			if test.wantErr != nil {
+				if !errors.Is(configErr, test.wantErr) {
+					t.Errorf("%v: wanted error %v but got %v", testIndex, test.wantErr, configErr)
+				}
+
+				return
+			}
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			var b bytes.Buffer
+
+			if err := config.ToSecretsHidden(&b); err != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, err)
+			}
+
+			if b.String() != test.want {
+				t.Errorf("%v: wanted\n%v\nbut got\n%v", testIndex, test.want, b.String())
+			}
+		})
+	}
+}
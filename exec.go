@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrExecNotPermitted indicates that a template tried to execute a command not in the allow-list.
+var ErrExecNotPermitted = errors.New("command execution not permitted")
+
+// execWaitDelay is the time the output of a command is still collected after it was terminated due to its timeout,
+// e.g. from processes it started itself.
+const execWaitDelay = 100 * time.Millisecond
+
+// Exec defines the commands templates may execute using the `exec` template function.
+type Exec struct {
+	// Commands is the allow-list of executables. They have to be given exactly as in the templates, either as name
+	// looked up in the PATH or as path.
+	Commands []string
+
+	// Timeout is the maximum execution time of a command. Zero means no limit.
+	Timeout time.Duration
+}
+
+// WithExec creates an Option that enables the `exec` template function, running the commands allowed by execution. The
+// function gives the standard output of the command, without leading and trailing white space:
+//
+//	token: {{ exec "token-helper" "--audience" "db" | quote }}
+//
+// If the command fails, the error contains its standard error output. Without this option, templates cannot execute
+// commands at all.
+func WithExec(execution Exec) Option {
+	return func(c configurable) error {
+		return c.setExec(execution)
+	}
+}
+
+func (c *Config[T]) setExec(execution Exec) error {
+	execution.Commands = slices.Clone(execution.Commands)
+	c.exec = &execution
+
+	return nil
+}
+
+// execCommand is the `exec` template function, running the given allow-listed command and giving its trimmed output.
+func (c *Config[T]) execCommand(name string, args ...string) (string, error) {
+	if !slices.Contains(c.exec.Commands, name) {
+		return "", fmt.Errorf("%w: %v", ErrExecNotPermitted, name)
+	}
+
+	ctx := context.Background()
+
+	if c.exec.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, c.exec.Timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, name, args...) //nolint:gosec // commands are restricted by the allow-list
+	cmd.Env = c.execEnv()
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = execWaitDelay
+
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("could not execute %v: %w: %v", name, err, msg)
+		}
+
+		return "", fmt.Errorf("could not execute %v: %w", name, err)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// execEnv gives the environment of the commands run by `exec`. In a sandbox, it only contains the allow-listed
+// variables. With an environment set by [WithEnv] or [WithEnvFunc], it contains the variables given to WithEnv and the
+// ones of the process environment, with the values of the environment set. Otherwise, it is nil, so commands see the
+// environment of the process.
+func (c *Config[T]) execEnv() []string {
+	var names []string
+
+	switch {
+	case c.sandbox != nil:
+		names = slices.Clone(c.sandbox.Env)
+	case c.envFunc != nil:
+		names = slices.Clone(c.envNames)
+
+		for _, v := range os.Environ() {
+			name, _, _ := strings.Cut(v, "=")
+			names = append(names, name)
+		}
+	default:
+		return nil
+	}
+
+	lookup := c.envFunc
+
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+
+	slices.Sort(names)
+
+	result := make([]string, 0, len(names))
+
+	for _, name := range slices.Compact(names) {
+		if value, found := lookup(name); found && name != "" {
+			result = append(result, name+"="+value)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlphaOne1/templig"
+)
+
+func TestExec(t *testing.T) {
+	t.Parallel()
+
+	shell := templig.Exec{Commands: []string{"echo", "sh"}, Timeout: 5 * time.Second}
+
+	tests := []struct {
+		in          []string
+		options     []templig.Option
+		wantName    string
+		wantErrIs   error
+		wantErrText string
+	}{
+		{ // 0
+			in:       []string{`name: {{ exec "echo" "  name0 " | quote }}`},
+			options:  []templig.Option{templig.WithExec(shell)},
+			wantName: "name0",
+		},
+		{ // 1
+			in:       []string{`id: 9`, `name: {{ exec "sh" "-c" "echo name1" }}`},
+			options:  []templig.Option{templig.WithExec(shell)},
+			wantName: "name1",
+		},
+		{ // 2
+			in:          []string{`name: {{ exec "echo" "name0" }}`},
+			wantErrText: "function \"exec\" not defined",
+		},
+		{ // 3
+			in:        []string{`name: {{ exec "cat" "testData/secret.txt" }}`},
+			options:   []templig.Option{templig.WithExec(shell)},
+			wantErrIs: templig.ErrExecNotPermitted,
+		},
+		{ // 4
+			in:          []string{`name: {{ exec "sh" "-c" "echo no token >&2; exit 3" }}`},
+			options:     []templig.Option{templig.WithExec(shell)},
+			wantErrText: "exit status 3: no token",
+		},
+		{ // 5
+			in: []string{`name: {{ exec "sh" "-c" "sleep 5" }}`},
+			options: []templig.Option{
+				templig.WithExec(templig.Exec{Commands: []string{"sh"}, Timeout: 50 * time.Millisecond}),
+			},
+			wantErrText: "deadline exceeded",
+		},
+		{ // 6
+			in: []string{`name: {{ exec "echo" "name0" }}`},
+			options: []templig.Option{
+				templig.WithExec(shell),
+				templig.WithSandbox(templig.Sandbox{}),
+			},
+			wantErrText: "function \"exec\" not defined",
+		},
+		{ // 7
+			in: []string{`name: {{ exec "sh" "-c" "echo $TEMPLIG_EXEC_NAME-${HOME:-none}" }}`},
+			options: []templig.Option{
+				templig.WithExec(shell),
+				templig.WithEnv(map[string]string{"TEMPLIG_EXEC_NAME": "name7"}),
+			},
+			wantName: "name7-none",
+		},
+		{ // 8
+			in: []string{`name: {{ exec "sh" "-c" "echo $TEMPLIG_EXEC_NAME-${TEMPLIG_EXEC_OTHER:-none}" }}`},
+			options: []templig.Option{
+				templig.WithExec(shell),
+				templig.WithEnv(map[string]string{"TEMPLIG_EXEC_NAME": "name8", "TEMPLIG_EXEC_OTHER": "other"}),
+				templig.WithSandbox(templig.Sandbox{Funcs: []string{"exec"}, Env: []string{"TEMPLIG_EXEC_NAME"}}),
+			},
+			wantName: "name8-none",
+		},
+		{ // 9
+			in: []string{`id: 9`, `name: {{ exec "sh" "-c" "echo $TEMPLIG_EXEC_NAME" }}`},
+			options: []templig.Option{
+				templig.WithExec(shell),
+				templig.WithEnv(map[string]string{"TEMPLIG_EXEC_NAME": "name9"}),
+			},
+			wantName: "name9",
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("Exec-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			options := test.options
+
+			for _, v := range test.in {
+				options = append(options, templig.WithReader(strings.NewReader(v)))
+			}
+
+			config, configErr := templig.New[TestConfig](options...)
+
+			if test.wantErrIs != nil || test.wantErrText != "" {
+				if configErr == nil {
+					t.Fatalf("%v: wanted error but got nil", testIndex)
+				}
+
+				if test.wantErrIs != nil && !errors.Is(configErr, test.wantErrIs) {
+					t.Errorf("%v: wanted error %v but got %v", testIndex, test.wantErrIs, configErr)
+				}
+
+				if !strings.Contains(configErr.Error(), test.wantErrText) {
+					t.Errorf("%v: wanted error containing %q but got %v", testIndex, test.wantErrText, configErr)
+				}
+
+				return
+			}
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			if config.Get().Name != test.wantName {
+				t.Errorf("%v: wanted name %v but got %v", testIndex, test.wantName, config.Get().Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"go.yaml.in/yaml/v4"
+)
+
+// fileAccess provides the template functions accessing files. How relative file names are resolved is determined by
+// its resolve function, which files can be accessed by its open and match functions.
+type fileAccess struct {
+	resolve func(fileName string) string
+	open    func(path string) (*os.File, error)
+	match   func(pattern string) ([]string, error)
+}
+
+// processFiles gives the file access of the process, relative file names are resolved against the working directory.
+func processFiles() fileAccess {
+	return fileAccess{resolve: filepath.Clean, open: openFile, match: filepath.Glob}
+}
+
+// fileAccess gives the file access of that specific instance, resolving relative file names against the given base
+// directory.
+func (c *Config[T]) fileAccess(baseDir string) fileAccess {
+	return fileAccess{
+		resolve: func(fileName string) string { return c.resolvePath(baseDir, fileName) },
+		open:    c.openFile,
+		match:   c.globFiles,
+	}
+}
+
+// openFile opens the file with the given name, wrapping errors with the file name.
+func openFile(fileName string) (*os.File, error) {
+	f, err := os.Open(filepath.Clean(fileName))
+
+	if err != nil {
+		return nil, fmt.Errorf("could not open %v: %w", fileName, err)
+	}
+
+	return f, nil
+}
+
+// funcs gives the template functions accessing files.
+func (a fileAccess) funcs() template.FuncMap {
+	return template.FuncMap{
+		"read":         a.read,
+		"readRequired": a.readRequired,
+		"readOptional": a.readOptional,
+		"fileExists":   a.exists,
+		"readYAML":     a.readYAML,
+		"readJSON":     a.readJSON,
+		"readLines":    a.readLines,
+		"readDir":      a.readDir,
+		"glob":         a.glob,
+		"readAll":      a.readAll,
+	}
+}
+
+// read is a template function to read a file and store its content into a string.
+// If the file does not exist, an empty string is generated, facilitating the use of `required` for customized
+// user interaction. Other errors, e.g. missing permissions, are reported.
+func (a fileAccess) read(fileName string) (any, error) {
+	content, err := a.readString(fileName)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+
+	return content, err
+}
+
+// readRequired is a template function to read a file and store its content into a string.
+// In contrast to read, a missing file is reported as error.
+func (a fileAccess) readRequired(fileName string) (any, error) {
+	return a.readString(fileName)
+}
+
+// readString reads the content of the file with the given name into a string.
+func (a fileAccess) readString(fileName string) (string, error) {
+	file, err := a.open(a.resolve(fileName))
+
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	content, readErr := io.ReadAll(file)
+
+	if readErr != nil {
+		return "", fmt.Errorf("could not read %v: %w", fileName, readErr)
+	}
+
+	return string(content), nil
+}
+
+// readOptional is a template function to read a file and store its content into a string.
+// If the file does not exist, the given default value is generated. In contrast to read, an existing but empty file
+// gives an empty string.
+func (a fileAccess) readOptional(fileName, defaultValue string) (any, error) {
+	content, err := a.readString(fileName)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return defaultValue, nil
+	}
+
+	return content, err
+}
+
+// exists is a template function checking if the file with the given name exists. Errors other than the file not
+// existing, e.g. missing permissions, are reported.
+func (a fileAccess) exists(fileName string) (bool, error) {
+	file, err := a.open(a.resolve(fileName))
+
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	_ = file.Close()
+
+	return true, nil
+}
+
+// readYAML is a template function to read a YAML file into maps and slices usable in templates.
+func (a fileAccess) readYAML(fileName string) (any, error) {
+	content, err := a.readString(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result any
+
+	if err := yaml.Unmarshal([]byte(content), &result); err != nil {
+		return nil, fmt.Errorf("could not parse %v: %w", fileName, err)
+	}
+
+	return result, nil
+}
+
+// readJSON is a template function to read a JSON file into maps and slices usable in templates.
+func (a fileAccess) readJSON(fileName string) (any, error) {
+	content, err := a.readString(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result any
+
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return nil, fmt.Errorf("could not parse %v: %w", fileName, err)
+	}
+
+	return result, nil
+}
+
+// readLines is a template function to read a file into a list of its lines, without line endings.
+func (a fileAccess) readLines(fileName string) ([]string, error) {
+	content, err := a.readString(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSuffix(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	if text == "" {
+		return []string{}, nil
+	}
+
+	return strings.Split(text, "\n"), nil
+}
+
+// readDir is a template function giving the sorted names of the entries of the given directory.
+func (a fileAccess) readDir(dirName string) ([]string, error) {
+	dir, err := a.open(a.resolve(dirName))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = dir.Close() }()
+
+	names, readErr := dir.Readdirnames(-1)
+
+	if readErr != nil {
+		return nil, fmt.Errorf("could not read directory %v: %w", dirName, readErr)
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// glob is a template function giving the sorted names of the files matching the given pattern, in the syntax of
+// [filepath.Match]. Relative patterns give names relative to the same directory, usable with the other functions.
+func (a fileAccess) glob(pattern string) ([]string, error) {
+	matches, err := a.match(a.resolve(pattern))
+
+	if err != nil {
+		return nil, fmt.Errorf("could not match %v: %w", pattern, err)
+	}
+
+	if filepath.IsAbs(pattern) {
+		return matches, nil
+	}
+
+	base, err := filepath.Abs(a.resolve("."))
+
+	if err != nil {
+		return nil, fmt.Errorf("could not match %v: %w", pattern, err)
+	}
+
+	for i, match := range matches {
+		if match, err = filepath.Abs(match); err == nil {
+			matches[i], err = filepath.Rel(base, match)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("could not match %v: %w", pattern, err)
+		}
+	}
+
+	return matches, nil
+}
+
+// readAll is a template function reading all files matching the given pattern. It gives a list of maps containing the
+// `name` of the file without directory, its `path` as given by glob and its `content`.
+func (a fileAccess) readAll(pattern string) ([]map[string]any, error) {
+	paths, err := a.glob(pattern)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]any, 0, len(paths))
+
+	for _, path := range paths {
+		content, readErr := a.readString(path)
+
+		if readErr != nil {
+			return nil, readErr
+		}
+
+		result = append(result, map[string]any{
+			"name":    filepath.Base(path),
+			"path":    path,
+			"content": content,
+		})
+	}
+
+	return result, nil
+}
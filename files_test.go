@@ -0,0 +1,253 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/AlphaOne1/templig"
+)
+
+func TestFileFuncs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in       string
+		options  []templig.Option
+		wantName string
+		wantErr  bool
+	}{
+		{ // 0
+			in:       `name: {{ read "testData/does_not_exist.txt" | quote }}`,
+			wantName: "",
+		},
+		{ // 1
+			in:      `name: {{ read "testData" | quote }}`,
+			wantErr: true,
+		},
+		{ // 2
+			in:      `name: {{ read "testData/secret.txt/x" | quote }}`,
+			wantErr: true,
+		},
+		{ // 3
+			in:       `name: {{ readRequired "testData/secret.txt" }}`,
+			wantName: "pass0",
+		},
+		{ // 4
+			in:      `name: {{ readRequired "testData/does_not_exist.txt" }}`,
+			wantErr: true,
+		},
+		{ // 5
+			in:       `name: {{ readOptional "testData/does_not_exist.txt" "none" }}`,
+			wantName: "none",
+		},
+		{ // 6
+			in:       `name: {{ readOptional "testData/test_empty.yaml" "none" | quote }}`,
+			wantName: "",
+		},
+		{ // 7
+			in:       `name: {{ readOptional "testData/secret.txt" "none" }}`,
+			wantName: "pass0",
+		},
+		{ // 8
+			in:      `name: {{ readOptional "testData" "none" }}`,
+			wantErr: true,
+		},
+		{ // 9
+			in:       `name: {{ fileExists "testData/secret.txt" }}-{{ fileExists "testData/does_not_exist.txt" }}`,
+			wantName: "true-false",
+		},
+		{ // 10
+			in:      `name: {{ fileExists "testData/secret.txt/x" }}`,
+			wantErr: true,
+		},
+		{ // 11
+			in:      `name: {{ fileExists "secret.txt" }}-{{ readOptional "../go.mod" "none" }}`,
+			options: []templig.Option{templig.WithSandbox(templig.Sandbox{ReadRoot: "testData"})},
+			wantErr: true,
+		},
+		{ // 12
+			in:       `name: {{ fileExists "secret.txt" }}-{{ readOptional "none.txt" "none" }}`,
+			options:  []templig.Option{templig.WithSandbox(templig.Sandbox{ReadRoot: "testData"})},
+			wantName: "true-none",
+		},
+		{ // 13
+			in:       `name: {{ (readJSON "testData/test_data.json").project_id }}`,
+			wantName: "templig-test",
+		},
+		{ // 14
+			in:       `name: {{ (readYAML "testData/test_data.yaml").service.name }}`,
+			wantName: "templig-test",
+		},
+		{ // 15
+			in:       `name: {{ index (readYAML "testData/test_data.yaml").service.ports 1 }}`,
+			wantName: "443",
+		},
+		{ // 16
+			in:       `name: {{ readLines "testData/test_lines.txt" | join "," }}`,
+			wantName: "cert0.pem,cert1.pem,cert2.pem",
+		},
+		{ // 17
+			in:       `name: {{ readLines "testData/test_empty.yaml" | len }}`,
+			wantName: "0",
+		},
+		{ // 18
+			in:      `name: {{ readJSON "testData/test_data.yaml" }}`,
+			wantErr: true,
+		},
+		{ // 19
+			in:      `name: {{ readYAML "testData/does_not_exist.yaml" }}`,
+			wantErr: true,
+		},
+		{ // 20
+			in:      `name: {{ readLines "testData/does_not_exist.txt" }}`,
+			wantErr: true,
+		},
+		{ // 21
+			in:       `name: {{ readDir "testData/include" | join "," }}`,
+			wantName: "conn.yaml,cycle_0.yaml,cycle_1.yaml,passes_0.yaml,passes_1.yaml,read_secret.yaml",
+		},
+		{ // 22
+			in:       `name: {{ glob "testData/include/passes_*.yaml" | join "," }}`,
+			wantName: "testData/include/passes_0.yaml,testData/include/passes_1.yaml",
+		},
+		{ // 23
+			in:       `name: {{ range readAll "testData/secret.tx?" }}{{ .name }}={{ .content }}@{{ .path }}{{ end }}`,
+			wantName: "secret.txt=pass0@testData/secret.txt",
+		},
+		{ // 24
+			in:       `name: {{ glob "testData/*.none" | len }}`,
+			wantName: "0",
+		},
+		{ // 25
+			in:      `name: {{ glob "testData/[" }}`,
+			wantErr: true,
+		},
+		{ // 26
+			in:      `name: {{ readDir "testData/secret.txt" }}`,
+			wantErr: true,
+		},
+		{ // 27
+			in:       `name: {{ glob "*.txt" | join "," }}-{{ readDir "include" | len }}`,
+			options:  []templig.Option{templig.WithSandbox(templig.Sandbox{ReadRoot: "testData"})},
+			wantName: "secret.txt,test_lines.txt-6",
+		},
+		{ // 28
+			in:      `name: {{ glob "../*.md" }}`,
+			options: []templig.Option{templig.WithSandbox(templig.Sandbox{ReadRoot: "testData"})},
+			wantErr: true,
+		},
+		{ // 29
+			in:      `name: {{ readDir ".." }}`,
+			options: []templig.Option{templig.WithSandbox(templig.Sandbox{ReadRoot: "testData"})},
+			wantErr: true,
+		},
+		{ // 30
+			in:      `name: {{ fileExists "../go.mod" }}`,
+			options: []templig.Option{templig.WithSandbox(templig.Sandbox{ReadRoot: "testData"})},
+			wantErr: true,
+		},
+		{ // 31
+			in:      `name: {{ fileExists "../does_not_exist.txt" }}`,
+			options: []templig.Option{templig.WithSandbox(templig.Sandbox{ReadRoot: "testData"})},
+			wantErr: true,
+		},
+		{ // 32
+			in:       `name: {{ glob "include/../include/passes_*.yaml" | join "," }}`,
+			options:  []templig.Option{templig.WithSandbox(templig.Sandbox{ReadRoot: "testData"})},
+			wantName: "include/passes_0.yaml,include/passes_1.yaml",
+		},
+		{ // 33
+			in:       `name: {{ range readAll "include/passes_?.yaml" }}{{ .path }},{{ end }}`,
+			options:  []templig.Option{templig.WithSandbox(templig.Sandbox{ReadRoot: "testData"})},
+			wantName: "include/passes_0.yaml,include/passes_1.yaml,",
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("FileFuncs-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			config, configErr := templig.New[TestConfig](
+				append(test.options, templig.WithReader(strings.NewReader(test.in)))...)
+
+			if test.wantErr {
+				if configErr == nil {
+					t.Errorf("%v: wanted error but got nil", testIndex)
+				}
+
+				return
+			}
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			if config.Get().Name != test.wantName {
+				t.Errorf("%v: wanted name %v but got %v", testIndex, test.wantName, config.Get().Name)
+			}
+		})
+	}
+}
+
+func TestSandboxGlobOutsideRoot(t *testing.T) {
+	t.Parallel()
+
+	for testIndex, pattern := range []string{"../*.mod", "/etc/pass*", "../testData/../*.mod"} {
+		_, err := templig.New[TestConfig](
+			templig.WithSandbox(templig.Sandbox{ReadRoot: "testData"}),
+			templig.WithReader(strings.NewReader(`name: {{ glob "`+pattern+`" }}`)))
+
+		if !errors.Is(err, templig.ErrSandboxViolation) {
+			t.Errorf("%v: wanted sandbox violation but got %v", testIndex, err)
+		}
+
+		if err != nil && (strings.Contains(err.Error(), "go.mod") || strings.Contains(err.Error(), "passwd")) {
+			t.Errorf("%v: wanted no matched files in the error but got %v", testIndex, err)
+		}
+	}
+}
+
+func TestGlobalFileFuncs(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(template.New("global").Funcs(templig.TemplateFunctions).Parse(
+		`{{ read "testData/secret.txt" }}-{{ readRequired "testData/secret.txt" }}-` +
+			`{{ readOptional "testData/none.txt" "none" }}-{{ fileExists "testData/none.txt" }}`))
+
+	var b strings.Builder
+
+	if err := tmpl.Execute(&b, nil); err != nil {
+		t.Fatalf("did not want error but got %v", err)
+	}
+
+	if want := "pass0-pass0-none-false"; b.String() != want {
+		t.Errorf("wanted %v but got %v", want, b.String())
+	}
+}
+
+func TestToYAML(t *testing.T) {
+	t.Parallel()
+
+	config, configErr := templig.New[TestConfig](templig.WithReader(strings.NewReader(`
+name: {{ toYAML "a: b" }}
+conn: {{ toYAMLIndent 4 (dict "passes" (readYAML "testData/test_data.yaml").service.ports) }}`)))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	if config.Get().Name != "a: b" {
+		t.Errorf("wanted name a: b but got %v", config.Get().Name)
+	}
+
+	if want := []string{"80", "443"}; !slices.Equal(config.Get().Conn.Passes, want) {
+		t.Errorf("wanted passes %v but got %v", want, config.Get().Conn.Passes)
+	}
+}
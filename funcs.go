@@ -5,26 +5,61 @@ package templig
 
 import (
 	"errors"
-	"io"
+	"fmt"
 	"maps"
 	"os"
-	"path/filepath"
+	"reflect"
 	"slices"
 	"strings"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
+	"go.yaml.in/yaml/v4"
 )
 
 // TemplateFunctions is a template.FuncMap that allows to globally remove the additional templig template functions or
 // even add own functions on top of what is already provided. For user-provided functions, please use the prefix `uP`,
 // that is guaranteed to never be used as a templig provided function. Access to this variable
-// is not synchronized, thus modifying it shall be done before working with templig.
+// is not synchronized, thus modifying it shall be done before working with templig. To customize the functions of a
+// single configuration only, use [WithFuncs] and [WithoutFuncs].
 var TemplateFunctions = template.FuncMap{ //nolint:gochecknoglobals
-	"arg":      argumentValue,
-	"hasArg":   argumentPresent,
-	"required": required,
-	"read":     readFile,
+	"arg":          argumentValue,
+	"args":         argumentValues,
+	"hasArg":       argumentPresent,
+	"required":     required,
+	"read":         readFile,
+	"readRequired": readRequired,
+	"readOptional": readOptional,
+	"fileExists":   fileExists,
+	"readYAML":     readYAML,
+	"readJSON":     readJSON,
+	"readLines":    readLines,
+	"readDir":      readDir,
+	"glob":         glob,
+	"readAll":      readAll,
+	"toYAML":       toYAML,
+	"toYAMLIndent": toYAMLIndent,
+	"secret":       secret,
+}
+
+// ErrNoFuncs indicates that no template functions were provided where at least one is required.
+var ErrNoFuncs = errors.New("no template functions given")
+
+// ErrNoEnvFunc indicates that no function to provide the environment variables was given.
+var ErrNoEnvFunc = errors.New("no environment function given")
+
+// defaultFunctions are the functions as provided by templig and sprig, before any modification of
+// [TemplateFunctions]. They are used to detect global customizations, that the instance bound functions must not
+// override.
+var defaultFunctions = templigFunctions() //nolint:gochecknoglobals
+
+// isDefaultFunction checks if the function with the given name in the given function map is still the one provided by
+// templig or sprig. If it was removed or replaced using [TemplateFunctions], it is not.
+func isDefaultFunction(funcs template.FuncMap, name string) bool {
+	f, found := funcs[name]
+	d, isDefault := defaultFunctions[name]
+
+	return found && isDefault && reflect.ValueOf(f).Pointer() == reflect.ValueOf(d).Pointer()
 }
 
 // templigFunctions gives all the functions that are enabled for the templating engine.
@@ -36,6 +71,100 @@ func templigFunctions() template.FuncMap {
 	return result
 }
 
+// WithFuncs creates an Option that adds the given functions to the template functions of that specific instance,
+// replacing functions of the same name. Unlike modifications of [TemplateFunctions], they do not affect other
+// instances.
+func WithFuncs(funcs template.FuncMap) Option {
+	return func(c configurable) error {
+		if len(funcs) == 0 {
+			return ErrNoFuncs
+		}
+
+		return c.addFuncs(funcs)
+	}
+}
+
+// WithoutFuncs creates an Option that removes the functions with the given names from the template functions of that
+// specific instance. Unlike modifications of [TemplateFunctions], they do not affect other instances.
+func WithoutFuncs(names ...string) Option {
+	return func(c configurable) error {
+		if len(names) == 0 {
+			return ErrNoFuncs
+		}
+
+		return c.removeFuncs(names...)
+	}
+}
+
+func (c *Config[T]) addFuncs(funcs template.FuncMap) error {
+	if c.funcs == nil {
+		c.funcs = make(template.FuncMap, len(funcs))
+	}
+
+	for name, f := range funcs {
+		c.funcs[name] = f
+		c.removedFuncs = slices.DeleteFunc(c.removedFuncs, func(s string) bool { return s == name })
+	}
+
+	return nil
+}
+
+func (c *Config[T]) removeFuncs(names ...string) error {
+	for _, name := range names {
+		delete(c.funcs, name)
+
+		if !slices.Contains(c.removedFuncs, name) {
+			c.removedFuncs = append(c.removedFuncs, name)
+		}
+	}
+
+	return nil
+}
+
+// templateFunctions gives the functions enabled for the templating engine of that specific instance.
+// The functions accessing arguments, files or the environment are bound to the instance, to honor its settings,
+// unless they were removed or replaced using [TemplateFunctions].
+// Relative file names are resolved against the given base directory, the plain texts given by `decrypt` are recorded in
+// decrypted.
+func (c *Config[T]) templateFunctions(baseDir string, decrypted map[string]struct{}) template.FuncMap {
+	result := templigFunctions()
+	bound := template.FuncMap{
+		"arg":       c.argumentValue,
+		"args":      c.argumentValues,
+		"hasArg":    c.argumentPresent,
+		"env":       c.env,
+		"expandenv": c.expandEnv,
+		"secret":    c.secretAccess(baseDir).resolve,
+	}
+
+	maps.Insert(bound, maps.All(c.fileAccess(baseDir).funcs()))
+
+	// functions removed or replaced globally stay that way
+	for name, f := range bound {
+		if isDefaultFunction(result, name) {
+			result[name] = f
+		}
+	}
+
+	result["decrypt"] = c.decryptFunction(decrypted)
+
+	if c.exec != nil {
+		result["exec"] = c.execCommand
+	}
+
+	if c.http != nil {
+		result["fetch"] = c.http.fetch
+	}
+
+	maps.Insert(result, maps.All(c.funcs))
+
+	for _, name := range c.removedFuncs {
+		delete(result, name)
+	}
+
+	return result
+}
+
 // required is a template function to indicate that the second argument cannot be empty or nil.
 func required(warn string, val any) (any, error) {
 	if s, ok := val.(string); val == nil || (ok && s == "") {
@@ -49,60 +178,316 @@ func required(warn string, val any) (any, error) {
 // If the file does not exist, an empty string is generated, facilitating the use of `required` for customized
 // user interaction.
 func readFile(fileName string) (any, error) {
-	file, err := os.Open(filepath.Clean(fileName))
+	return processFiles().read(fileName)
+}
+
+// readRequired is a template function to read a file, reporting an error if it does not exist.
+func readRequired(fileName string) (any, error) {
+	return processFiles().readRequired(fileName)
+}
+
+// readOptional is a template function to read a file, giving the default value if it does not exist.
+func readOptional(fileName, defaultValue string) (any, error) {
+	return processFiles().readOptional(fileName, defaultValue)
+}
+
+// fileExists is a template function checking if the file with the given name exists.
+func fileExists(fileName string) (bool, error) {
+	return processFiles().exists(fileName)
+}
+
+// readYAML is a template function to read a YAML file into maps and slices.
+func readYAML(fileName string) (any, error) {
+	return processFiles().readYAML(fileName)
+}
+
+// readJSON is a template function to read a JSON file into maps and slices.
+func readJSON(fileName string) (any, error) {
+	return processFiles().readJSON(fileName)
+}
+
+// readLines is a template function to read a file into a list of its lines.
+func readLines(fileName string) ([]string, error) {
+	return processFiles().readLines(fileName)
+}
+
+// readDir is a template function giving the names of the entries of a directory.
+func readDir(dirName string) ([]string, error) {
+	return processFiles().readDir(dirName)
+}
+
+// glob is a template function giving the names of the files matching a pattern.
+func glob(pattern string) ([]string, error) {
+	return processFiles().glob(pattern)
+}
+
+// readAll is a template function reading all files matching a pattern.
+func readAll(pattern string) ([]map[string]any, error) {
+	return processFiles().readAll(pattern)
+}
+
+// toYAML is a template function encoding the given value as YAML, without trailing newline.
+func toYAML(value any) (string, error) {
+	var buf strings.Builder
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2) //nolint:mnd
+
+	if err := enc.Encode(value); err != nil {
+		return "", fmt.Errorf("could not encode YAML: %w", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("could not encode YAML: %w", err)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// toYAMLIndent is a template function encoding the given value as YAML, starting on a new line with every line
+// indented by the given number of spaces. This allows embedding structured values as in `key: {{ toYAMLIndent 2 .x }}`.
+func toYAMLIndent(indent int, value any) (string, error) {
+	encoded, err := toYAML(value)
 
 	if err != nil {
-		return "", nil
+		return "", err
 	}
 
-	defer func() { _ = file.Close() }()
+	prefix := strings.Repeat(" ", max(indent, 0))
 
-	content, readErr := io.ReadAll(file)
+	return "\n" + prefix + strings.ReplaceAll(encoded, "\n", "\n"+prefix), nil
+}
+
+// secret is a template function giving the value of the secret addressed by the given URI.
+func secret(uri string) (string, error) {
+	return processSecrets().resolve(uri)
+}
+
+// env is a template function giving the value of the named environment variable.
+func (c *Config[T]) env(name string) (string, error) {
+	return c.lookupEnv(name)
+}
+
+// expandEnv is a template function replacing ${var} or $var in the given string by the value of the named
+// environment variables.
+func (c *Config[T]) expandEnv(s string) (string, error) {
+	var errs []error
+
+	result := os.Expand(s, func(name string) string {
+		value, err := c.lookupEnv(name)
+
+		if err != nil {
+			errs = append(errs, err)
+		}
 
-	return string(content), readErr
+		return value
+	})
+
+	return result, errors.Join(errs...)
 }
 
-func argumentIndexMatch(name string) func(string) bool {
-	return func(s string) bool {
-		tmp := strings.TrimLeft(s, "-")
+// WithEnv creates an Option that sets the environment variables seen by the `env` and `expandenv` template functions,
+// by [WithProfilesFromEnv] and by the commands run by `exec`, instead of the environment of the process. Variables not
+// in env are unset.
+func WithEnv(env map[string]string) Option {
+	env = maps.Clone(env)
+
+	return withEnvFunc(func(name string) (string, bool) {
+		value, found := env[name]
 
-		return len(tmp) != len(s) &&
-			(tmp == name ||
-				len(tmp) > len(name) && strings.HasPrefix(tmp, name) && tmp[len(name)] == '=')
+		return value, found
+	}, slices.Sorted(maps.Keys(env)))
+}
+
+// WithEnvFunc creates an Option that sets a function providing the environment variables seen by the `env` and
+// `expandenv` template functions, by [WithProfilesFromEnv] and by the commands run by `exec`, instead of the
+// environment of the process. The function has the semantics of [os.LookupEnv]. As the variables it provides cannot
+// be listed, commands only see the variables of the process environment, with the values given by the function.
+func WithEnvFunc(lookup func(name string) (string, bool)) Option {
+	return func(c configurable) error {
+		if lookup == nil {
+			return ErrNoEnvFunc
+		}
+
+		return c.setEnvFunc(lookup, nil)
 	}
 }
 
-func argumentValue(name string) (any, error) {
-	index := slices.IndexFunc(os.Args, argumentIndexMatch(name))
+// withEnvFunc creates an Option that sets the function providing the environment variables, alongside the names of
+// the variables known to be provided by it.
+func withEnvFunc(lookup func(name string) (string, bool), names []string) Option {
+	return func(c configurable) error {
+		return c.setEnvFunc(lookup, names)
+	}
+}
+
+func (c *Config[T]) setEnvFunc(lookup func(name string) (string, bool), names []string) error {
+	c.envFunc = lookup
+	c.envNames = names
+
+	return nil
+}
+
+// getEnv gives the value of the environment variable with the given name, using the environment provider of that
+// specific instance, if set.
+func (c *Config[T]) getEnv(name string) string {
+	if c.envFunc == nil {
+		return os.Getenv(name)
+	}
+
+	value, _ := c.envFunc(name)
+
+	return value
+}
+
+// WithArgs creates an Option that sets the command line arguments seen by the `arg`, `hasArg` and `args` template
+// functions, instead of the arguments of the program in [os.Args]. The arguments are given without the program name.
+func WithArgs(args []string) Option {
+	return func(c configurable) error {
+		return c.setArgs(args)
+	}
+}
+
+func (c *Config[T]) setArgs(args []string) error {
+	c.args = slices.Clone(args)
+	c.argsSet = true
+
+	return nil
+}
+
+// WithPOSIXFlags creates an Option that lets the `arg`, `hasArg` and `args` template functions and
+// [WithProfilesFromArg] find single-letter flags in POSIX-style clusters of short flags, so that `-vxf` is read as
+// `-v -x -f`. Without it, an argument with a single dash is one flag, e.g. `-config`.
+func WithPOSIXFlags() Option {
+	return func(c configurable) error {
+		return c.enablePOSIXFlags()
+	}
+}
+
+func (c *Config[T]) enablePOSIXFlags() error {
+	c.posixFlags = true
 
-	// handle arguments that give the value using assignment
-	if index >= 0 && strings.Contains(os.Args[index], "=") {
-		argument := strings.SplitN(os.Args[index], "=", 2)
+	return nil
+}
+
+// arguments gives the command line arguments seen by the template functions of that specific instance.
+func (c *Config[T]) arguments() []string {
+	if c.argsSet {
+		return c.args
+	}
+
+	return os.Args[1:]
+}
+
+// argumentLookup gives all values of the argument with the given name in args and whether it is present at all.
+// Arguments are recognized with one or two leading dashes, their value is either given by assignment
+// (`--name=value`) or in the next argument, if that does not start with a dash. If clusters is set, single-letter names
+// are also found in clusters of short flags (`-abc`), where only the last flag of the cluster can take a value from the
+// next argument. Arguments following `--` are not considered.
+func argumentLookup(args []string, name string, clusters bool) ([]string, bool) {
+	var values []string
+	present := false
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" {
+			break
+		}
+
+		key := strings.TrimLeft(arg, "-")
+
+		if len(key) == len(arg) || key == "" {
+			continue
+		}
+
+		key, value, assigned := strings.Cut(key, "=")
+		inCluster := clusters && isShortFlagCluster(arg, name)
+
+		switch {
+		case key == name && assigned:
+			values = append(values, value)
+		case key == name || inCluster && strings.HasSuffix(key, name):
+			// handle arguments with the value in the next argument
+			// (that then may not start with a dash)
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				i++
+				values = append(values, args[i])
+			}
+		case inCluster:
+			// flags in the middle of a cluster have no value
+		default:
+			continue
+		}
+
+		present = true
+	}
+
+	return values, present
+}
+
+// isShortFlagCluster checks if arg is a cluster of single-letter flags (`-abc`) that contains the single-letter name.
+func isShortFlagCluster(arg, name string) bool {
+	if len(name) != 1 || len(arg) < 3 || arg[0] != '-' || arg[1] == '-' {
+		return false
+	}
 
-		if len(argument) == 2 {
-			return argument[1], nil
+	for _, r := range arg[1:] {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
 		}
 	}
 
-	// handle arguments with the value in the next argument
-	// (that then may not start with a dash)
-	if index >= 0 &&
-		len(os.Args) > index+1 &&
-		!strings.HasPrefix(os.Args[index+1], "-") {
+	return strings.Contains(arg[1:], name)
+}
 
-		return os.Args[index+1], nil
+// argumentValue is a template function giving the first value of the command line argument with the given name, an
+// empty string if it is not present or has no value.
+func argumentValue(name string) (any, error) {
+	return argumentString(os.Args[1:], name, false), nil
+}
+
+// argumentString gives the first value of the argument with the given name in args, an empty string if it is not
+// present or has no value.
+func argumentString(args []string, name string, clusters bool) string {
+	if values, _ := argumentLookup(args, name, clusters); len(values) > 0 {
+		return values[0]
 	}
 
 	// no argument value given
-	return "", nil
+	return ""
+}
+
+// argumentValues is a template function giving all values of the repeatedly given command line argument with the
+// given name.
+func argumentValues(name string) (any, error) {
+	values, _ := argumentLookup(os.Args[1:], name, false)
+
+	return values, nil
 }
 
+// argumentPresent is a template function checking if the command line argument with the given name is present.
 func argumentPresent(name string) (any, error) {
-	index := slices.IndexFunc(os.Args, argumentIndexMatch(name))
+	_, present := argumentLookup(os.Args[1:], name, false)
 
-	if index >= 0 {
-		return true, nil
-	}
+	return present, nil
+}
+
+// argumentValue is the instance-specific variant of the `arg` template function.
+func (c *Config[T]) argumentValue(name string) (any, error) {
+	return argumentString(c.arguments(), name, c.posixFlags), nil
+}
+
+// argumentValues is the instance-specific variant of the `args` template function.
+func (c *Config[T]) argumentValues(name string) (any, error) {
+	values, _ := argumentLookup(c.arguments(), name, c.posixFlags)
+
+	return values, nil
+}
+
+// argumentPresent is the instance-specific variant of the `hasArg` template function.
+func (c *Config[T]) argumentPresent(name string) (any, error) {
+	_, present := argumentLookup(c.arguments(), name, c.posixFlags)
 
-	return false, nil
+	return present, nil
 }
@@ -0,0 +1,348 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/AlphaOne1/templig"
+)
+
+func TestFuncs(t *testing.T) {
+	t.Parallel()
+
+	upper := template.FuncMap{"uPUpper": strings.ToUpper}
+
+	tests := []struct {
+		in       []string
+		options  []templig.Option
+		wantName string
+		wantErr  bool
+	}{
+		{ // 0
+			in:       []string{`name: {{ "name0" | uPUpper }}`},
+			options:  []templig.Option{templig.WithFuncs(upper)},
+			wantName: "NAME0",
+		},
+		{ // 1
+			in:      []string{`name: {{ "name0" | uPUpper }}`},
+			wantErr: true,
+		},
+		{ // 2
+			in:       []string{`id: 9`, `name: {{ "name0" | uPUpper }}`},
+			options:  []templig.Option{templig.WithFuncs(upper)},
+			wantName: "NAME0",
+		},
+		{ // 3
+			in:      []string{`name: {{ "name0" | upper }}`},
+			options: []templig.Option{templig.WithoutFuncs("upper")},
+			wantErr: true,
+		},
+		{ // 4
+			in:      []string{`id: 9`, `name: {{ "name0" | upper }}`},
+			options: []templig.Option{templig.WithoutFuncs("upper")},
+			wantErr: true,
+		},
+		{ // 5
+			in: []string{`id: 9`, `name: {{ "name0" | upper }}`},
+			options: []templig.Option{
+				templig.WithoutFuncs("upper"),
+				templig.WithFuncs(template.FuncMap{"upper": strings.ToTitle}),
+			},
+			wantName: "NAME0",
+		},
+		{ // 6
+			in: []string{`name: {{ "name0" | uPUpper }}`},
+			options: []templig.Option{
+				templig.WithFuncs(upper),
+				templig.WithoutFuncs("uPUpper"),
+			},
+			wantErr: true,
+		},
+		{ // 7
+			in:       []string{`name: {{ read "testData/secret.txt" | upper }}`},
+			options:  []templig.Option{templig.WithoutFuncs("env", "expandenv")},
+			wantName: "PASS0",
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("Funcs-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			options := test.options
+
+			for _, v := range test.in {
+				options = append(options, templig.WithReader(strings.NewReader(v)))
+			}
+
+			config, configErr := templig.New[TestConfig](options...)
+
+			if test.wantErr {
+				if configErr == nil {
+					t.Errorf("%v: wanted error but got nil", testIndex)
+				}
+
+				return
+			}
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			if config.Get().Name != test.wantName {
+				t.Errorf("%v: wanted name %v but got %v", testIndex, test.wantName, config.Get().Name)
+			}
+		})
+	}
+}
+
+func TestNoFuncs(t *testing.T) {
+	t.Parallel()
+
+	for _, opt := range []templig.Option{templig.WithFuncs(nil), templig.WithoutFuncs()} {
+		_, err := templig.New[TestConfig](
+			templig.WithFile("testData/test_config_0.yaml"),
+			opt)
+
+		if !errors.Is(err, templig.ErrNoFuncs) {
+			t.Errorf("giving no functions should have returned an error")
+		}
+	}
+}
+
+func TestArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in       string
+		args     []string
+		posix    bool
+		wantName string
+		wantErr  bool
+	}{
+		{ // 0
+			in:       `name: {{ arg "param0" }}`,
+			args:     []string{"--param0", "paramVal0"},
+			wantName: "paramVal0",
+		},
+		{ // 1
+			in:       `name: {{ arg "param0" | quote }}`,
+			args:     []string{"--", "--param0", "paramVal0"},
+			wantName: "",
+		},
+		{ // 2
+			in:       `name: {{ hasArg "param0" }}`,
+			args:     []string{"run", "--", "--param0"},
+			wantName: "false",
+		},
+		{ // 3
+			in:       `name: {{ args "p" | join "," }}`,
+			args:     []string{"-p", "a", "--p=b", "-x", "--p", "c"},
+			wantName: "a,b,c",
+		},
+		{ // 4
+			in:       `name: {{ arg "p" }}`,
+			args:     []string{"-p", "a", "-p", "b"},
+			wantName: "a",
+		},
+		{ // 5
+			in:       `name: {{ hasArg "v" }}-{{ hasArg "x" }}-{{ arg "f" }}`,
+			args:     []string{"-vxf", "file.txt"},
+			posix:    true,
+			wantName: "true-true-file.txt",
+		},
+		{ // 6
+			in:       `name: {{ arg "v" | quote }}`,
+			args:     []string{"-vf", "file.txt"},
+			posix:    true,
+			wantName: "",
+		},
+		{ // 7
+			in:       `name: {{ hasArg "v" }}`,
+			args:     []string{"--vf"},
+			wantName: "false",
+		},
+		{ // 8
+			in:       `name: {{ args "p" | len }}`,
+			args:     nil,
+			wantName: "0",
+		},
+		{ // 9
+			in:      `name: {{ arg "param0" | required "param0 required" }}`,
+			args:    []string{"--param0"},
+			wantErr: true,
+		},
+		{ // 10
+			in:       `name: {{ hasArg "g" }}-{{ arg "g" | quote }}-{{ arg "config" }}`,
+			args:     []string{"-config", "prod.yaml"},
+			wantName: `false-""-prod.yaml`,
+		},
+		{ // 11
+			in:       `name: {{ hasArg "v" }}-{{ hasArg "vxf" }}-{{ arg "vxf" }}`,
+			args:     []string{"-vxf", "file.txt"},
+			wantName: "false-true-file.txt",
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("Args-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			options := []templig.Option{
+				templig.WithArgs(test.args),
+				templig.WithReader(strings.NewReader(`id: 9`)),
+				templig.WithReader(strings.NewReader(test.in)),
+			}
+
+			if test.posix {
+				options = append(options, templig.WithPOSIXFlags())
+			}
+
+			config, configErr := templig.New[TestConfig](options...)
+
+			if test.wantErr {
+				if configErr == nil {
+					t.Errorf("%v: wanted error but got nil", testIndex)
+				}
+
+				return
+			}
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			if config.Get().Name != test.wantName {
+				t.Errorf("%v: wanted name %v but got %v", testIndex, test.wantName, config.Get().Name)
+			}
+		})
+	}
+}
+
+func TestEnv(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"TEMPLIG_TEST_NAME": "envName"}
+
+	tests := []struct {
+		in       []string
+		options  []templig.Option
+		wantName string
+		wantErr  bool
+	}{
+		{ // 0
+			in:       []string{`name: {{ env "TEMPLIG_TEST_NAME" }}`},
+			options:  []templig.Option{templig.WithEnv(env)},
+			wantName: "envName",
+		},
+		{ // 1
+			in:       []string{`id: 9`, `name: {{ expandenv "${TEMPLIG_TEST_NAME}-$HOME" }}`},
+			options:  []templig.Option{templig.WithEnv(env)},
+			wantName: "envName-",
+		},
+		{ // 2
+			in: []string{`name: {{ env "TEMPLIG_TEST_NAME" }}`},
+			options: []templig.Option{
+				templig.WithEnvFunc(func(name string) (string, bool) { return strings.ToLower(name), true }),
+			},
+			wantName: "templig_test_name",
+		},
+		{ // 3
+			in: []string{`name: {{ env "TEMPLIG_TEST_NAME" }}`},
+			options: []templig.Option{
+				templig.WithEnv(env),
+				templig.WithSandbox(templig.Sandbox{}),
+			},
+			wantErr: true,
+		},
+		{ // 4
+			in: []string{`name: {{ env "TEMPLIG_TEST_NAME" }}`},
+			options: []templig.Option{
+				templig.WithEnv(env),
+				templig.WithSandbox(templig.Sandbox{Env: []string{"TEMPLIG_TEST_NAME"}}),
+			},
+			wantName: "envName",
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("Env-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			options := test.options
+
+			for _, v := range test.in {
+				options = append(options, templig.WithReader(strings.NewReader(v)))
+			}
+
+			config, configErr := templig.New[TestConfig](options...)
+
+			if test.wantErr {
+				if configErr == nil {
+					t.Errorf("%v: wanted error but got nil", testIndex)
+				}
+
+				return
+			}
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			if config.Get().Name != test.wantName {
+				t.Errorf("%v: wanted name %v but got %v", testIndex, test.wantName, config.Get().Name)
+			}
+		})
+	}
+}
+
+func TestNoEnvFunc(t *testing.T) {
+	t.Parallel()
+
+	_, err := templig.New[TestConfig](
+		templig.WithFile("testData/test_config_0.yaml"),
+		templig.WithEnvFunc(nil))
+
+	if !errors.Is(err, templig.ErrNoEnvFunc) {
+		t.Errorf("giving no environment function should have returned an error")
+	}
+}
+
+//nolint:paralleltest // modifies the global TemplateFunctions
+func TestGlobalFuncsCustomized(t *testing.T) {
+	read := templig.TemplateFunctions["read"]
+	hasArg := templig.TemplateFunctions["hasArg"]
+
+	defer func() {
+		templig.TemplateFunctions["read"] = read
+		templig.TemplateFunctions["hasArg"] = hasArg
+	}()
+
+	delete(templig.TemplateFunctions, "read")
+	templig.TemplateFunctions["hasArg"] = func(string) bool { return true }
+
+	_, readErr := templig.New[TestConfig](
+		templig.WithReader(strings.NewReader(`name: {{ read "testData/secret.txt" }}`)))
+
+	if readErr == nil || !strings.Contains(readErr.Error(), `function "read" not defined`) {
+		t.Errorf("wanted globally removed function to be undefined but got %v", readErr)
+	}
+
+	config, configErr := templig.New[TestConfig](
+		templig.WithArgs([]string{}),
+		templig.WithReader(strings.NewReader(`name: {{ hasArg "verbose" }}`)))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	if config.Get().Name != "true" {
+		t.Errorf("wanted globally replaced function to be used but got %v", config.Get().Name)
+	}
+}
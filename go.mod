@@ -6,12 +6,14 @@ module github.com/AlphaOne1/templig
 go 1.26
 
 require (
+	filippo.io/age v1.3.2
 	github.com/Masterminds/sprig/v3 v3.3.0
 	go.yaml.in/yaml/v4 v4.0.0-rc.6
 )
 
 require (
 	dario.cat/mergo v1.0.2 // indirect
+	filippo.io/hpke v0.4.0 // indirect
 	github.com/Masterminds/goutils v1.1.1 // indirect
 	github.com/Masterminds/semver/v3 v3.5.0 // indirect
 	github.com/bitfield/gotestdox v0.2.3 // indirect
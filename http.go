@@ -0,0 +1,368 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v4"
+)
+
+// DefaultHTTPTimeout is the timeout of a single HTTP request, if none is given.
+const DefaultHTTPTimeout = 10 * time.Second
+
+// maxHTTPRedirects is the maximum number of redirects followed for a single HTTP request.
+const maxHTTPRedirects = 10
+
+var (
+	// ErrHostNotPermitted indicates that a URL does not use HTTP(S) or addresses a host not in the allow-list.
+	ErrHostNotPermitted = errors.New("host not permitted")
+
+	// ErrHTTPStatus indicates that an HTTP request was not answered successfully.
+	ErrHTTPStatus = errors.New("unexpected HTTP status")
+
+	// ErrInvalidJSONPath indicates that a JSON path does not address a value in the fetched document.
+	ErrInvalidJSONPath = errors.New("invalid JSON path")
+)
+
+// HTTP defines how configuration data is fetched using HTTP(S), by the `fetch` template function enabled using
+// [WithHTTP] or by the sources created using [HTTP.Source].
+type HTTP struct {
+	// Hosts is the allow-list of hosts that can be accessed, either as host name or as host name and port.
+	Hosts []string
+
+	// Timeout is the maximum duration of a single request. If it is zero, [DefaultHTTPTimeout] is used.
+	Timeout time.Duration
+
+	// Retries is the number of times a request is retried after network errors or server-side errors.
+	Retries int
+
+	// RetryDelay is the time to wait before retrying a request.
+	RetryDelay time.Duration
+
+	// CacheDir is the directory fetched documents are cached in. Cached documents are revalidated using their ETag,
+	// so unchanged documents are not transferred again. If it is empty, no caching takes place.
+	CacheDir string
+
+	// Client is the HTTP client used for the requests. If it is nil, a default client is used.
+	Client *http.Client
+}
+
+// WithHTTP creates an Option that enables the `fetch` template function, giving the document at the given URL:
+//
+//	region: {{ fetch "https://metadata.internal/instance.json#placement.region" | quote }}
+//
+// A fragment of the URL is interpreted as dotted path of the value to extract from a JSON document. Without
+// fragment, the document is given as string. Without this option, templates cannot access the network at all.
+func WithHTTP(settings HTTP) Option {
+	return func(c configurable) error {
+		return c.setHTTP(settings)
+	}
+}
+
+func (c *Config[T]) setHTTP(settings HTTP) error {
+	settings.Hosts = slices.Clone(settings.Hosts)
+	c.http = &settings
+
+	return nil
+}
+
+// Source creates a configuration source for the document at the given URL, to be used with [WithNodeSource]. As for
+// `fetch`, a fragment of the URL selects a value of a JSON document as configuration. The document is data only and
+// not templated, so remote documents cannot access files, the environment or other hosts.
+func (h HTTP) Source(rawURL string) NodeSource {
+	return httpSource{settings: h, url: rawURL}
+}
+
+// httpSource is a configuration source fetched using HTTP(S).
+type httpSource struct {
+	settings HTTP
+	url      string
+}
+
+// Name gives the URL of the source.
+func (s httpSource) Name() string {
+	if u, err := url.Parse(s.url); err == nil {
+		return u.Redacted()
+	}
+
+	return s.url
+}
+
+// Node fetches the current document of the source, parsing it as YAML, so also JSON, document.
+func (s httpSource) Node() (*yaml.Node, error) {
+	value, err := s.settings.fetch(s.url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var node yaml.Node
+
+	if text, isText := value.(string); isText {
+		err = yaml.Unmarshal([]byte(text), &node)
+	} else {
+		err = node.Encode(value)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %v: %w", s.Name(), err)
+	}
+
+	if node.Kind == 0 {
+		// empty document
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil
+	}
+
+	return &node, nil
+}
+
+// fetch is the `fetch` template function, giving the document at the given URL, or the value selected by its
+// fragment.
+func (h HTTP) fetch(rawURL string) (any, error) {
+	u, err := h.checkURL(rawURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fragment := u.Fragment
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	body, err := h.get(u)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if fragment == "" {
+		return string(body), nil
+	}
+
+	var document any
+
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, fmt.Errorf("could not parse %v: %w", u.Redacted(), err)
+	}
+
+	return jsonPathValue(document, fragment)
+}
+
+// checkURL parses the given URL and checks that it is permitted.
+func (h HTTP) checkURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not parse URL: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("%w: unsupported scheme %q", ErrHostNotPermitted, u.Scheme)
+	}
+
+	if !h.hostPermitted(u) {
+		return nil, fmt.Errorf("%w: %v", ErrHostNotPermitted, u.Host)
+	}
+
+	return u, nil
+}
+
+// hostPermitted checks if the host of the given URL is in the allow-list.
+func (h HTTP) hostPermitted(u *url.URL) bool {
+	return slices.Contains(h.Hosts, u.Host) || slices.Contains(h.Hosts, u.Hostname())
+}
+
+// client gives the HTTP client to use, only following redirects to permitted hosts.
+func (h HTTP) client() *http.Client {
+	client := new(http.Client)
+
+	if h.Client != nil {
+		*client = *h.Client
+	}
+
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxHTTPRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxHTTPRedirects) //nolint:err113
+		}
+
+		if !h.hostPermitted(req.URL) {
+			return fmt.Errorf("%w: redirect to %v", ErrHostNotPermitted, req.URL.Host)
+		}
+
+		return nil
+	}
+
+	return client
+}
+
+// get gives the document at the given URL, retrying failed requests and using the cache, if configured.
+func (h HTTP) get(u *url.URL) ([]byte, error) {
+	cached, etag := h.cached(u)
+	client := h.client()
+
+	var errs []error
+
+	for attempt := 0; attempt <= h.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(h.RetryDelay)
+		}
+
+		body, notModified, retry, err := h.request(client, u, etag)
+
+		switch {
+		case err == nil && notModified:
+			return cached, nil
+		case err == nil:
+			return body, nil
+		case !retry:
+			return nil, err
+		default:
+			errs = append(errs, err)
+		}
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// request performs a single request for the given URL. If an ETag is given, the document is only transferred if it
+// changed. It indicates if the document was not modified, and if a failed request should be retried.
+func (h HTTP) request(
+	client *http.Client,
+	u *url.URL,
+	etag string) (body []byte, notModified bool, retry bool, err error) {
+
+	timeout := h.Timeout
+
+	if timeout == 0 {
+		timeout = DefaultHTTPTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+
+	if reqErr != nil {
+		return nil, false, false, fmt.Errorf("could not fetch %v: %w", u.Redacted(), reqErr)
+	}
+
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, doErr := client.Do(req)
+
+	if doErr != nil {
+		return nil, false, !errors.Is(doErr, ErrHostNotPermitted), fmt.Errorf("could not fetch %v: %w", u.Redacted(), doErr)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && etag != "":
+		return nil, true, false, nil
+	case resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests:
+		return nil, false, true, fmt.Errorf("could not fetch %v: %w %v", u.Redacted(), ErrHTTPStatus, resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return nil, false, false, fmt.Errorf("could not fetch %v: %w %v", u.Redacted(), ErrHTTPStatus, resp.Status)
+	}
+
+	body, readErr := io.ReadAll(resp.Body)
+
+	if readErr != nil {
+		return nil, false, true, fmt.Errorf("could not fetch %v: %w", u.Redacted(), readErr)
+	}
+
+	h.cache(u, body, resp.Header.Get("ETag"))
+
+	return body, false, false, nil
+}
+
+// cacheFile gives the name of the file the document of the given URL is cached in.
+func (h HTTP) cacheFile(u *url.URL) string {
+	sum := sha256.Sum256([]byte(u.String()))
+
+	return filepath.Join(h.CacheDir, hex.EncodeToString(sum[:]))
+}
+
+// cached gives the cached document of the given URL and its ETag, if any.
+func (h HTTP) cached(u *url.URL) ([]byte, string) {
+	if h.CacheDir == "" {
+		return nil, ""
+	}
+
+	name := h.cacheFile(u)
+
+	etag, etagErr := os.ReadFile(name + ".etag")
+	body, bodyErr := os.ReadFile(name)
+
+	if etagErr != nil || bodyErr != nil {
+		return nil, ""
+	}
+
+	return body, string(etag)
+}
+
+// cache stores the document of the given URL with its ETag in the cache. Documents without ETag are not cached, as
+// they cannot be revalidated. Failing to cache is not an error, the document is just fetched again next time.
+func (h HTTP) cache(u *url.URL, body []byte, etag string) {
+	if h.CacheDir == "" || etag == "" {
+		return
+	}
+
+	if err := os.MkdirAll(h.CacheDir, 0o700); err != nil {
+		return
+	}
+
+	name := h.cacheFile(u)
+
+	if err := os.WriteFile(name, body, 0o600); err == nil {
+		_ = os.WriteFile(name+".etag", []byte(etag), 0o600)
+	}
+}
+
+// jsonPathValue gives the value at the given dotted path of mapping keys and list indices in the given document.
+func jsonPathValue(document any, path string) (any, error) {
+	result := document
+
+	for segment := range strings.SplitSeq(path, ".") {
+		switch current := result.(type) {
+		case map[string]any:
+			value, found := current[segment]
+
+			if !found {
+				return nil, fmt.Errorf("%w: no key %q in %v", ErrInvalidJSONPath, segment, path)
+			}
+
+			result = value
+		case []any:
+			index, err := strconv.Atoi(segment)
+
+			if err != nil || index < 0 || index >= len(current) {
+				return nil, fmt.Errorf("%w: no index %q in %v", ErrInvalidJSONPath, segment, path)
+			}
+
+			result = current[index]
+		default:
+			return nil, fmt.Errorf("%w: no value %q in %v", ErrInvalidJSONPath, segment, path)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,271 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/AlphaOne1/templig"
+)
+
+const testDocument = `{"id": 9, "name": "httpName", "conn": {"url": "https://www.tests.to", "passes": ["a", "b"]}}`
+
+func newTestServer(t *testing.T) (*httptest.Server, templig.HTTP) {
+	t.Helper()
+
+	var failures atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/doc.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(testDocument))
+	})
+	mux.HandleFunc("/wrapped.json", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data": ` + testDocument + `}`))
+	})
+	mux.HandleFunc("/templated.yaml", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 9, "name": "{{ env \"HOME\" }}"}`))
+	})
+	mux.HandleFunc("/flaky", func(w http.ResponseWriter, _ *http.Request) {
+		if failures.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_, _ = w.Write([]byte("flakyName"))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://not-permitted.example/doc.json", http.StatusFound)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	u, _ := url.Parse(server.URL)
+
+	return server, templig.HTTP{Hosts: []string{u.Host}, Client: server.Client()}
+}
+
+func TestFetch(t *testing.T) {
+	t.Parallel()
+
+	server, settings := newTestServer(t)
+
+	retrying := settings
+	retrying.Retries = 2
+
+	tests := []struct {
+		in        string
+		settings  *templig.HTTP
+		wantName  string
+		wantErrIs error
+	}{
+		{ // 0
+			in:       `name: {{ fetch "` + server.URL + `/doc.json#name" }}`,
+			settings: &settings,
+			wantName: "httpName",
+		},
+		{ // 1
+			in:       `name: {{ fetch "` + server.URL + `/doc.json#conn.passes.1" }}`,
+			settings: &settings,
+			wantName: "b",
+		},
+		{ // 2
+			in:       `name: {{ (fetch "` + server.URL + `/doc.json" | fromJson).conn.url }}`,
+			settings: &settings,
+			wantName: "https://www.tests.to",
+		},
+		{ // 3
+			in:        `name: {{ fetch "` + server.URL + `/doc.json#conn.passes.2" }}`,
+			settings:  &settings,
+			wantErrIs: templig.ErrInvalidJSONPath,
+		},
+		{ // 4
+			in:        `name: {{ fetch "` + server.URL + `/missing" }}`,
+			settings:  &settings,
+			wantErrIs: templig.ErrHTTPStatus,
+		},
+		{ // 5
+			in:        `name: {{ fetch "http://not-permitted.example/doc.json" }}`,
+			settings:  &settings,
+			wantErrIs: templig.ErrHostNotPermitted,
+		},
+		{ // 6
+			in:        `name: {{ fetch "file:///etc/passwd" }}`,
+			settings:  &settings,
+			wantErrIs: templig.ErrHostNotPermitted,
+		},
+		{ // 7
+			in:        `name: {{ fetch "` + server.URL + `/redirect" }}`,
+			settings:  &settings,
+			wantErrIs: templig.ErrHostNotPermitted,
+		},
+		{ // 8
+			in:       `name: {{ fetch "` + server.URL + `/doc.json#name" }}`,
+			wantName: "",
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("Fetch-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			options := []templig.Option{templig.WithReader(strings.NewReader(test.in))}
+
+			if test.settings != nil {
+				options = append(options, templig.WithHTTP(*test.settings))
+			}
+
+			config, configErr := templig.New[TestConfig](options...)
+
+			if test.settings == nil {
+				if configErr == nil {
+					t.Errorf("%v: wanted error without HTTP enabled but got nil", testIndex)
+				}
+
+				return
+			}
+
+			if test.wantErrIs != nil {
+				if !errors.Is(configErr, test.wantErrIs) {
+					t.Errorf("%v: wanted error %v but got %v", testIndex, test.wantErrIs, configErr)
+				}
+
+				return
+			}
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			if config.Get().Name != test.wantName {
+				t.Errorf("%v: wanted name %v but got %v", testIndex, test.wantName, config.Get().Name)
+			}
+		})
+	}
+}
+
+func TestFetchRetries(t *testing.T) {
+	t.Parallel()
+
+	server, settings := newTestServer(t)
+	in := `name: {{ fetch "` + server.URL + `/flaky" }}`
+
+	if _, err := templig.New[TestConfig](
+		templig.WithHTTP(settings),
+		templig.WithReader(strings.NewReader(in))); !errors.Is(err, templig.ErrHTTPStatus) {
+		t.Errorf("wanted error %v without retries but got %v", templig.ErrHTTPStatus, err)
+	}
+
+	settings.Retries = 2
+
+	config, configErr := templig.New[TestConfig](
+		templig.WithHTTP(settings),
+		templig.WithReader(strings.NewReader(in)))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	if config.Get().Name != "flakyName" {
+		t.Errorf("wanted name flakyName but got %v", config.Get().Name)
+	}
+}
+
+func TestHTTPSource(t *testing.T) {
+	t.Parallel()
+
+	server, settings := newTestServer(t)
+
+	config, configErr := templig.New[TestConfig](
+		templig.WithFile("testData/test_config_0.yaml"),
+		templig.WithNodeSource(settings.Source(server.URL+"/wrapped.json#data")),
+	)
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	if config.Get().Name != "httpName" {
+		t.Errorf("wanted name httpName but got %v", config.Get().Name)
+	}
+
+	if got := len(config.Get().Conn.Passes); got != 4 {
+		t.Errorf("wanted 4 passes but got %v", got)
+	}
+
+	if origin, _ := config.Origin("conn.url"); origin.Source != server.URL+"/wrapped.json#data" {
+		t.Errorf("wanted origin %v but got %v", server.URL+"/wrapped.json#data", origin)
+	}
+}
+
+func TestHTTPSourceNotTemplated(t *testing.T) {
+	t.Parallel()
+
+	server, settings := newTestServer(t)
+
+	config, configErr := templig.New[TestConfig](templig.WithNodeSource(settings.Source(server.URL + "/templated.yaml")))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	if want := `{{ env "HOME" }}`; config.Get().Name != want {
+		t.Errorf("wanted name %v but got %v", want, config.Get().Name)
+	}
+}
+
+func TestHTTPSourceCache(t *testing.T) {
+	t.Parallel()
+
+	var requests, transfers atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		transfers.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(testDocument))
+	}))
+	t.Cleanup(server.Close)
+
+	u, _ := url.Parse(server.URL)
+	settings := templig.HTTP{Hosts: []string{u.Hostname()}, CacheDir: t.TempDir()}
+
+	for i := range 3 {
+		config, configErr := templig.New[TestConfig](templig.WithNodeSource(settings.Source(server.URL)))
+
+		if configErr != nil {
+			t.Fatalf("%v: did not want error but got %v", i, configErr)
+		}
+
+		if config.Get().Name != "httpName" {
+			t.Errorf("%v: wanted name httpName but got %v", i, config.Get().Name)
+		}
+	}
+
+	if requests.Load() != 3 || transfers.Load() != 1 {
+		t.Errorf("wanted 3 requests with 1 transfer but got %v with %v", requests.Load(), transfers.Load())
+	}
+}
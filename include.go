@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v4"
+)
+
+// IncludeTag is the YAML tag marking a node to be replaced by the content of the named file(s).
+const IncludeTag = "!include"
+
+var (
+	// ErrIncludeCycle indicates that a file directly or indirectly includes itself.
+	ErrIncludeCycle = errors.New("include cycle detected")
+
+	// ErrInvalidInclude indicates that an include does not name a file or a sequence of files.
+	ErrInvalidInclude = errors.New("invalid include")
+)
+
+// resolveIncludes replaces all nodes tagged with [IncludeTag] in the given node structure by the content of the
+// files they name. A sequence of file names is merged in order using [MergeYAMLNodes]. Relative file names are
+// resolved relative to the directory of the including source file. The origins of the included values are added to the
+// given origins map.
+func (c *Config[T]) resolveIncludes(
+	node *yaml.Node,
+	path []string,
+	origins map[string]Origin,
+	s source,
+	includeStack []string) error {
+
+	if node == nil {
+		return nil
+	}
+
+	if node.Tag == IncludeTag {
+		return c.include(node, path, origins, s, includeStack)
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, v := range node.Content {
+			if err := c.resolveIncludes(v, path, origins, s, includeStack); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if err := c.resolveIncludes(
+				node.Content[i+1],
+				append(path, node.Content[i].Value),
+				origins,
+				s,
+				includeStack); err != nil {
+
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, v := range node.Content {
+			if err := c.resolveIncludes(v, append(path, strconv.Itoa(i)), origins, s, includeStack); err != nil {
+				return err
+			}
+		}
+	default:
+		// scalars contain no further nodes, aliases point to nodes that are resolved on their own
+	}
+
+	return nil
+}
+
+// include replaces the given include node by the content of the file(s) it names.
+func (c *Config[T]) include(
+	node *yaml.Node,
+	path []string,
+	origins map[string]Origin,
+	s source,
+	includeStack []string) error {
+
+	var includeNames []string
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		includeNames = []string{node.Value}
+	case yaml.SequenceNode:
+		for _, v := range node.Content {
+			if v.Kind != yaml.ScalarNode {
+				return fmt.Errorf("%w: line %d: sequence of file names expected", ErrInvalidInclude, node.Line)
+			}
+
+			includeNames = append(includeNames, v.Value)
+		}
+	default:
+		return fmt.Errorf("%w: line %d: file name expected", ErrInvalidInclude, node.Line)
+	}
+
+	var result *yaml.Node
+	resultOrigins := make(map[string]Origin)
+
+	for _, includeName := range includeNames {
+		included, includedOrigins, err := c.includeFile(includeName, s, includeStack)
+
+		if err != nil {
+			return err
+		}
+
+		mergeOrigins(resultOrigins, result, includedOrigins)
+
+		if result == nil {
+			result = included
+		} else if result, err = MergeYAMLNodes(result, included); err != nil {
+			return fmt.Errorf("could not merge included file %v: %w", includeName, err)
+		}
+	}
+
+	if result == nil || len(result.Content) != 1 {
+		return fmt.Errorf("%w: line %d: no content to include", ErrInvalidInclude, node.Line)
+	}
+
+	*node = *result.Content[0]
+
+	prefix := strings.Join(path, ".")
+
+	for k, v := range resultOrigins {
+		origins[strings.Trim(prefix+"."+k, ".")] = v
+	}
+
+	return nil
+}
+
+// includeFile loads the file with the given name, resolved relative to the including source. The included file is
+// templated using the delimiters of the including source.
+func (c *Config[T]) includeFile(
+	includeName string,
+	s source,
+	includeStack []string) (*yaml.Node, map[string]Origin, error) {
+
+	includeName = c.resolvePath(c.baseDir(s), includeName)
+
+	absName, absErr := filepath.Abs(includeName)
+
+	if absErr != nil {
+		return nil, nil, fmt.Errorf("could not include %v: %w", includeName, absErr)
+	}
+
+	if slices.Contains(includeStack, absName) {
+		return nil, nil, fmt.Errorf("could not include %v: %w", includeName, ErrIncludeCycle)
+	}
+
+	f, openErr := c.openFile(includeName)
+
+	if openErr != nil {
+		return nil, nil, fmt.Errorf("could not include %v: %w", includeName, openErr)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	included, includedOrigins, loadErr := c.load(
+		f,
+		source{fileName: includeName, delims: s.delims},
+		append(slices.Clip(includeStack), absName))
+
+	if loadErr != nil {
+		return nil, nil, fmt.Errorf("could not include %v: %w", includeName, loadErr)
+	}
+
+	return included, includedOrigins, nil
+}
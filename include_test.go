@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AlphaOne1/templig"
+)
+
+func TestInclude(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		options    []templig.Option
+		wantPasses []string
+		wantErr    bool
+		wantErrIs  error
+	}{
+		{ // 0
+			options: []templig.Option{
+				templig.WithFile("testData/test_config_include.yaml"),
+			},
+			wantPasses: []string{"pass0", "pass1"},
+		},
+		{ // 1
+			options: []templig.Option{
+				templig.WithFile("testData/test_config_include.yaml"),
+				templig.WithValue("pass1", "passValue"),
+			},
+			wantPasses: []string{"pass0", "passValue"},
+		},
+		{ // 2
+			options: []templig.Option{
+				templig.WithFile("testData/test_config_include.yaml"),
+				templig.WithFile("testData/test_config_0_overlay.yaml"),
+			},
+			wantPasses: []string{"pass0", "pass1", "unknown"},
+		},
+		{ // 3
+			options: []templig.Option{
+				templig.WithReader(strings.NewReader("id: 9\nconn: !include testData/include/conn.yaml")),
+			},
+			wantPasses: []string{"pass0", "pass1"},
+		},
+		{ // 4
+			options: []templig.Option{
+				templig.WithReader(strings.NewReader("conn: !include testData/include/cycle_0.yaml")),
+			},
+			wantErr:   true,
+			wantErrIs: templig.ErrIncludeCycle,
+		},
+		{ // 5
+			options: []templig.Option{
+				templig.WithReader(strings.NewReader("conn: !include {a: b}")),
+			},
+			wantErr:   true,
+			wantErrIs: templig.ErrInvalidInclude,
+		},
+		{ // 6
+			options: []templig.Option{
+				templig.WithReader(strings.NewReader("conn: !include [[a]]")),
+			},
+			wantErr:   true,
+			wantErrIs: templig.ErrInvalidInclude,
+		},
+		{ // 7
+			options: []templig.Option{
+				templig.WithReader(strings.NewReader("conn: !include testData/does_not_exist.yaml")),
+			},
+			wantErr: true,
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("Include-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			config, configErr := templig.New[TestConfig](test.options...)
+
+			if test.wantErr {
+				if configErr == nil {
+					t.Fatalf("%v: wanted error but got nil", testIndex)
+				}
+
+				if test.wantErrIs != nil && !errors.Is(configErr, test.wantErrIs) {
+					t.Errorf("%v: wanted error %v but got %v", testIndex, test.wantErrIs, configErr)
+				}
+
+				return
+			}
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			if config.Get().Conn == nil || config.Get().Conn.URL != "https://www.tests.to" {
+				t.Fatalf("%v: included connection not found", testIndex)
+			}
+
+			if !slices.Equal(config.Get().Conn.Passes, test.wantPasses) {
+				t.Errorf("%v: wanted passes %v but got %v", testIndex, test.wantPasses, config.Get().Conn.Passes)
+			}
+		})
+	}
+}
+
+func TestIncludeOrigin(t *testing.T) {
+	t.Parallel()
+
+	config, configErr := templig.FromFile[TestConfig](
+		"testData/test_config_include.yaml",
+		"testData/test_config_0_overlay.yaml")
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	tests := map[string]string{
+		"name":          "testData/test_config_include.yaml:5:7",
+		"conn.url":      "testData/include/conn.yaml:4:6",
+		"conn.passes.0": "testData/include/passes_0.yaml:4:3",
+		"conn.passes.1": "testData/include/passes_1.yaml:4:3",
+		"conn.passes.2": "testData/test_config_0_overlay.yaml:6:9",
+	}
+
+	for path, want := range tests {
+		if got, _ := config.Origin(path); got.String() != want {
+			t.Errorf("%v: wanted origin %v but got %v", path, want, got)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v4"
+)
+
+// ErrKeyConflict indicates that a key is used both for a value and as prefix of other keys.
+var ErrKeyConflict = errors.New("key used both as value and as prefix")
+
+// NodeFromKeys builds a node structure from flat keys, whose segments are separated by the given separator, e.g.
+// `database.host` with separator `.`. Values are given as plain scalars, so their type is inferred as in YAML
+// documents. Mappings whose keys are exactly the indices 0 to n-1 become sequences.
+func NodeFromKeys(values map[string]string, separator string) (*yaml.Node, error) {
+	root := &keyTree{}
+
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if err := root.insert(strings.Split(key, separator), values[key]); err != nil {
+			return nil, fmt.Errorf("%w: %v", err, key)
+		}
+	}
+
+	return root.node(), nil
+}
+
+// keyTree is an intermediate tree of key segments, either holding a value or children.
+type keyTree struct {
+	value    *string
+	children map[string]*keyTree
+}
+
+// insert adds the given value under the given key segments.
+func (t *keyTree) insert(segments []string, value string) error {
+	if len(segments) == 0 {
+		if t.children != nil {
+			return ErrKeyConflict
+		}
+
+		t.value = &value
+
+		return nil
+	}
+
+	if t.value != nil {
+		return ErrKeyConflict
+	}
+
+	if t.children == nil {
+		t.children = make(map[string]*keyTree)
+	}
+
+	child, found := t.children[segments[0]]
+
+	if !found {
+		child = &keyTree{}
+		t.children[segments[0]] = child
+	}
+
+	return child.insert(segments[1:], value)
+}
+
+// node converts the tree to a node structure.
+func (t *keyTree) node() *yaml.Node {
+	if t.value != nil {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: *t.value}
+	}
+
+	keys := make([]string, 0, len(t.children))
+
+	for key := range t.children {
+		keys = append(keys, key)
+	}
+
+	if t.isSequence() {
+		result := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+
+		for i := range len(keys) {
+			result.Content = append(result.Content, t.children[strconv.Itoa(i)].node())
+		}
+
+		return result
+	}
+
+	slices.Sort(keys)
+
+	result := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+
+	for _, key := range keys {
+		result.Content = append(result.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			t.children[key].node())
+	}
+
+	return result
+}
+
+// isSequence checks if the keys of the children are exactly the indices 0 to n-1.
+func (t *keyTree) isSequence() bool {
+	if len(t.children) == 0 {
+		return false
+	}
+
+	for i := range len(t.children) {
+		if _, found := t.children[strconv.Itoa(i)]; !found {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/AlphaOne1/templig"
+)
+
+func TestNodeFromKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in        map[string]string
+		separator string
+		want      string
+		wantErr   error
+	}{
+		{ // 0
+			in:        map[string]string{},
+			separator: ".",
+			want:      "{}",
+		},
+		{ // 1
+			in:        map[string]string{"id": "9", "name": "Name0"},
+			separator: ".",
+			want:      "id: 9\nname: Name0",
+		},
+		{ // 2
+			in:        map[string]string{"conn/url": "https://www.tests.to", "conn/passes/1": "b", "conn/passes/0": "a"},
+			separator: "/",
+			want:      "conn:\n    passes:\n        - a\n        - b\n    url: https://www.tests.to",
+		},
+		{ // 3
+			in:        map[string]string{"list.0": "a", "list.2": "b"},
+			separator: ".",
+			want:      "list:\n    \"0\": a\n    \"2\": b",
+		},
+		{ // 4
+			in:        map[string]string{"conn": "x", "conn.url": "y"},
+			separator: ".",
+			wantErr:   templig.ErrKeyConflict,
+		},
+		{ // 5
+			in:        map[string]string{"conn.url.host": "x", "conn.url": "y"},
+			separator: ".",
+			wantErr:   templig.ErrKeyConflict,
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("NodeFromKeys-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			node, err := templig.NodeFromKeys(test.in, test.separator)
+
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("%v: wanted error %v but got %v", testIndex, test.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, err)
+			}
+
+			var buf bytes.Buffer
+
+			if encodeErr := yaml.NewEncoder(&buf).Encode(node); encodeErr != nil {
+				t.Fatalf("%v: could not encode node: %v", testIndex, encodeErr)
+			}
+
+			if got := strings.TrimSpace(buf.String()); got != test.want {
+				t.Errorf("%v: wanted\n%v\nbut got\n%v", testIndex, test.want, got)
+			}
+		})
+	}
+}
+
+type KeySource map[string]string
+
+func (s KeySource) Name() string {
+	return "keys"
+}
+
+func (s KeySource) Node() (*yaml.Node, error) {
+	return templig.NodeFromKeys(s, ".")
+}
+
+func TestNodeSource(t *testing.T) {
+	t.Parallel()
+
+	c, err := templig.New[TestConfig](
+		templig.WithFile("testData/test_config_0.yaml"),
+		templig.WithNodeSource(KeySource{"id": "23", "name": "{{ not templated }}", "conn.passes.0": "pass2"}))
+
+	if err != nil {
+		t.Fatalf("did not want error but got %v", err)
+	}
+
+	if c.Get().ID != 23 || c.Get().Name != "{{ not templated }}" {
+		t.Errorf("wanted id 23 and name {{ not templated }} but got %v and %v", c.Get().ID, c.Get().Name)
+	}
+
+	if len(c.Get().Conn.Passes) != 3 {
+		t.Errorf("wanted 3 passes but got %v", c.Get().Conn.Passes)
+	}
+
+	if origin, _ := c.Origin("conn.passes.2"); origin.Source != "keys" {
+		t.Errorf("wanted origin keys but got %v", origin)
+	}
+
+	single, singleErr := templig.New[TestConfig](templig.WithNodeSource(KeySource{"id": "9"}))
+
+	if singleErr != nil {
+		t.Fatalf("did not want error but got %v", singleErr)
+	}
+
+	if single.Get().ID != 9 {
+		t.Errorf("wanted id 9 but got %v", single.Get().ID)
+	}
+}
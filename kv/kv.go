@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package kv maps key-value stores, like etcd, Consul or Redis, into templig configurations. It is built against the
+// Store interface, so the backends and their dependencies are chosen by the application. The in-memory Memory store
+// serves as reference implementation and for tests.
+//
+// A key prefix of a store can be used as overlay source:
+//
+//	c, err := templig.New[Config](
+//	    templig.WithFile("my_config.yaml"),
+//	    templig.WithNodeSource(kv.NewSource(store, "my_app/")),
+//	)
+//
+// Single keys can be read in templates using the `kv` function:
+//
+//	c, err := templig.New[Config](
+//	    templig.WithFuncs(kv.Funcs(store)),
+//	    templig.WithFile("my_config.yaml"),
+//	)
+package kv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/AlphaOne1/templig"
+)
+
+// DefaultSeparator is the separator of key segments used by sources created using [NewSource].
+const DefaultSeparator = "/"
+
+// ErrWatchNotSupported indicates that a store cannot watch for changes.
+var ErrWatchNotSupported = errors.New("watching not supported by store")
+
+// Store is the interface of key-value stores.
+type Store interface {
+	// Get gives the value of the given key and whether it exists.
+	Get(ctx context.Context, key string) (string, bool, error)
+
+	// List gives all keys starting with the given prefix and their values.
+	List(ctx context.Context, prefix string) (map[string]string, error)
+}
+
+// Watcher is the interface of key-value stores that can watch for changes.
+type Watcher interface {
+	// Watch signals changes of keys starting with the given prefix on the returned channel. The channel is closed
+	// when the given context is done.
+	Watch(ctx context.Context, prefix string) (<-chan struct{}, error)
+}
+
+// Source is a configuration source mapping the keys with a prefix of a key-value store into a configuration subtree.
+// It is used with [templig.WithNodeSource].
+type Source struct {
+	// Store is the key-value store to read from.
+	Store Store
+
+	// Prefix is the prefix of the keys to read, it is not part of the resulting configuration paths. It ends at a
+	// segment boundary, e.g. the prefix `app` covers `app/id`, but not `app2/id`.
+	Prefix string
+
+	// Separator separates the segments of the keys, that become the levels of the configuration.
+	Separator string
+
+	// Timeout is the maximum duration to read the keys. Zero means no limit.
+	Timeout time.Duration
+}
+
+// NewSource creates a source for the keys of the store with the given prefix, using the [DefaultSeparator].
+func NewSource(store Store, prefix string) *Source {
+	return &Source{
+		Store:     store,
+		Prefix:    prefix,
+		Separator: DefaultSeparator,
+	}
+}
+
+// Name gives the name of the source, used e.g. to record the origin of values.
+func (s *Source) Name() string {
+	return "kv:" + s.Prefix
+}
+
+// Node gives the current content of the source.
+func (s *Source) Node() (*yaml.Node, error) {
+	ctx := context.Background()
+
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	values, err := s.Store.List(ctx, s.Prefix)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not list %v: %w", s.Prefix, err)
+	}
+
+	stripped := make(map[string]string, len(values))
+
+	for key, value := range values {
+		if key, inside := s.relativeKey(key); inside && key != "" {
+			stripped[key] = value
+		}
+	}
+
+	node, nodeErr := templig.NodeFromKeys(stripped, s.Separator)
+
+	if nodeErr != nil {
+		return nil, fmt.Errorf("could not map %v: %w", s.Prefix, nodeErr)
+	}
+
+	return node, nil
+}
+
+// relativeKey gives the given key without the prefix and the separator following it. Keys not below the prefix, e.g.
+// `app2/id` for the prefix `app`, are reported as outside, as the prefix has to end at a segment boundary.
+func (s *Source) relativeKey(key string) (string, bool) {
+	rest, found := strings.CutPrefix(key, s.Prefix)
+
+	if !found {
+		return "", false
+	}
+
+	if s.Prefix == "" || strings.HasSuffix(s.Prefix, s.Separator) || rest == "" {
+		return rest, true
+	}
+
+	return strings.CutPrefix(rest, s.Separator)
+}
+
+// Watch signals changes of the keys of the source on the returned channel, so the application can load its
+// configuration anew. The channel is closed when the given context is done. If the store cannot watch for changes,
+// [ErrWatchNotSupported] is returned.
+func (s *Source) Watch(ctx context.Context) (<-chan struct{}, error) {
+	watcher, canWatch := s.Store.(Watcher)
+
+	if !canWatch {
+		return nil, ErrWatchNotSupported
+	}
+
+	changes, err := watcher.Watch(ctx, s.Prefix)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not watch %v: %w", s.Prefix, err)
+	}
+
+	return changes, nil
+}
+
+// Funcs gives the `kv` template function reading single keys of the given store, to be used with
+// [templig.WithFuncs]. Like `read` for files, it gives an empty string for missing keys, facilitating the use of
+// `required` or `default`.
+func Funcs(store Store) template.FuncMap {
+	return template.FuncMap{
+		"kv": func(key string) (string, error) {
+			value, _, err := store.Get(context.Background(), key)
+
+			if err != nil {
+				return "", fmt.Errorf("could not get %v: %w", key, err)
+			}
+
+			return value, nil
+		},
+	}
+}
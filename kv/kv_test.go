@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AlphaOne1/templig"
+	"github.com/AlphaOne1/templig/kv"
+)
+
+type TestConn struct {
+	URL    string   `yaml:"url"`
+	Passes []string `yaml:"passes"`
+}
+
+type TestConfig struct {
+	ID   int       `yaml:"id"`
+	Name string    `yaml:"name"`
+	Conn *TestConn `yaml:"conn,omitempty"`
+}
+
+var errStore = errors.New("store error")
+
+// BrokenStore is a store failing on every access.
+type BrokenStore struct{}
+
+func (BrokenStore) Get(_ context.Context, _ string) (string, bool, error) {
+	return "", false, errStore
+}
+
+func (BrokenStore) List(_ context.Context, _ string) (map[string]string, error) {
+	return nil, errStore
+}
+
+func TestSource(t *testing.T) {
+	t.Parallel()
+
+	store := kv.NewMemory(map[string]string{
+		"app/id":            "9",
+		"app/name":          "kvName",
+		"app/conn/url":      "https://www.tests.to",
+		"app/conn/passes/0": "pass0",
+		"app/conn/passes/1": "pass1",
+		"other/name":        "otherName",
+	})
+
+	config, configErr := templig.New[TestConfig](
+		templig.WithReader(strings.NewReader("id: 1\nname: readerName")),
+		templig.WithNodeSource(kv.NewSource(store, "app/")))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	if config.Get().ID != 9 || config.Get().Name != "kvName" {
+		t.Errorf("wanted id 9 and name kvName but got %v and %v", config.Get().ID, config.Get().Name)
+	}
+
+	if config.Get().Conn == nil || len(config.Get().Conn.Passes) != 2 {
+		t.Fatalf("wanted 2 passes but got %v", config.Get().Conn)
+	}
+
+	if origin, _ := config.Origin("name"); origin.Source != "kv:app/" {
+		t.Errorf("wanted origin kv:app/ but got %v", origin)
+	}
+}
+
+func TestSourceSeparator(t *testing.T) {
+	t.Parallel()
+
+	store := kv.NewMemory(map[string]string{"app.id": "9", "app.conn.url": "https://www.tests.to"})
+	source := &kv.Source{Store: store, Prefix: "app", Separator: "."}
+
+	config, configErr := templig.New[TestConfig](templig.WithNodeSource(source))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	if config.Get().ID != 9 || config.Get().Conn.URL != "https://www.tests.to" {
+		t.Errorf("wanted id 9 and url https://www.tests.to but got %v and %v", config.Get().ID, config.Get().Conn)
+	}
+}
+
+func TestSourceSiblingPrefix(t *testing.T) {
+	t.Parallel()
+
+	store := kv.NewMemory(map[string]string{
+		"app/id":    "9",
+		"app2/id":   "2",
+		"app2/name": "siblingName",
+		"apple":     "x",
+	})
+
+	for i, prefix := range []string{"app", "app/"} {
+		t.Run(fmt.Sprintf("SiblingPrefix-%d", i), func(t *testing.T) {
+			t.Parallel()
+
+			config, configErr := templig.New[map[string]any](templig.WithNodeSource(kv.NewSource(store, prefix)))
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", i, configErr)
+			}
+
+			if got := *config.Get(); len(got) != 1 || got["id"] != 9 {
+				t.Errorf("%v: wanted only id 9 but got %v", i, got)
+			}
+		})
+	}
+}
+
+func TestSourceErrors(t *testing.T) {
+	t.Parallel()
+
+	if _, err := templig.New[TestConfig](
+		templig.WithNodeSource(kv.NewSource(BrokenStore{}, "app/"))); !errors.Is(err, errStore) {
+		t.Errorf("wanted error %v but got %v", errStore, err)
+	}
+
+	conflicting := kv.NewMemory(map[string]string{"app/conn": "x", "app/conn/url": "y"})
+
+	if _, err := templig.New[TestConfig](
+		templig.WithNodeSource(kv.NewSource(conflicting, "app/"))); !errors.Is(err, templig.ErrKeyConflict) {
+		t.Errorf("wanted error %v but got %v", templig.ErrKeyConflict, err)
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	t.Parallel()
+
+	store := kv.NewMemory(map[string]string{"app/name": "kvName"})
+
+	config, configErr := templig.New[TestConfig](
+		templig.WithFuncs(kv.Funcs(store)),
+		templig.WithReader(strings.NewReader(`name: {{ kv "app/name" }}-{{ kv "app/none" | default "none" }}`)))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	if config.Get().Name != "kvName-none" {
+		t.Errorf("wanted name kvName-none but got %v", config.Get().Name)
+	}
+
+	if _, err := templig.New[TestConfig](
+		templig.WithFuncs(kv.Funcs(BrokenStore{})),
+		templig.WithReader(strings.NewReader(`name: {{ kv "app/name" }}`))); !errors.Is(err, errStore) {
+		t.Errorf("wanted error %v but got %v", errStore, err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	store := kv.NewMemory(nil)
+	ctx, cancel := context.WithCancel(t.Context())
+
+	changes, err := kv.NewSource(store, "app/").Watch(ctx)
+
+	if err != nil {
+		t.Fatalf("did not want error but got %v", err)
+	}
+
+	store.Set("other/name", "otherName")
+	store.Set("app/name", "kvName")
+
+	if _, ok := <-changes; !ok {
+		t.Errorf("wanted change signal but channel was closed")
+	}
+
+	select {
+	case <-changes:
+		t.Errorf("did not want change signal for other prefix")
+	default:
+	}
+
+	cancel()
+
+	for range changes {
+		// drain until closed
+	}
+
+	if _, err := kv.NewSource(BrokenStore{}, "app/").Watch(t.Context()); !errors.Is(err, kv.ErrWatchNotSupported) {
+		t.Errorf("wanted error %v but got %v", kv.ErrWatchNotSupported, err)
+	}
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package kv
+
+import (
+	"context"
+	"maps"
+	"strings"
+	"sync"
+)
+
+// Memory is an in-memory key-value store. It is the reference implementation of [Store] and [Watcher] and can be
+// used in tests. It is safe for concurrent use.
+type Memory struct {
+	mu       sync.RWMutex
+	values   map[string]string
+	watchers map[*memoryWatch]struct{}
+}
+
+// memoryWatch is a single watch of a prefix.
+type memoryWatch struct {
+	prefix  string
+	changes chan struct{}
+}
+
+// NewMemory creates an in-memory store holding a copy of the given values.
+func NewMemory(values map[string]string) *Memory {
+	result := &Memory{
+		values:   maps.Clone(values),
+		watchers: make(map[*memoryWatch]struct{}),
+	}
+
+	if result.values == nil {
+		result.values = make(map[string]string)
+	}
+
+	return result
+}
+
+// Get gives the value of the given key and whether it exists.
+func (m *Memory) Get(_ context.Context, key string) (string, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	value, found := m.values[key]
+
+	return value, found, nil
+}
+
+// List gives all keys starting with the given prefix and their values.
+func (m *Memory) List(_ context.Context, prefix string) (map[string]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make(map[string]string)
+
+	for key, value := range m.values {
+		if strings.HasPrefix(key, prefix) {
+			result[key] = value
+		}
+	}
+
+	return result, nil
+}
+
+// Set sets the value of the given key, signaling the change to the watchers of its prefixes.
+func (m *Memory) Set(key, value string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.values[key] = value
+	m.notify(key)
+}
+
+// Delete removes the given key, signaling the change to the watchers of its prefixes.
+func (m *Memory) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, found := m.values[key]; found {
+		delete(m.values, key)
+		m.notify(key)
+	}
+}
+
+// Watch signals changes of keys starting with the given prefix on the returned channel. Changes happening while a
+// signal is still pending are merged into it. The channel is closed when the given context is done.
+func (m *Memory) Watch(ctx context.Context, prefix string) (<-chan struct{}, error) {
+	watch := &memoryWatch{prefix: prefix, changes: make(chan struct{}, 1)}
+
+	m.mu.Lock()
+	m.watchers[watch] = struct{}{}
+	m.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		delete(m.watchers, watch)
+		close(watch.changes)
+	}()
+
+	return watch.changes, nil
+}
+
+// notify signals the change of the given key to the watchers of its prefixes. The lock has to be held.
+func (m *Memory) notify(key string) {
+	for watch := range m.watchers {
+		if strings.HasPrefix(key, watch.prefix) {
+			select {
+			case watch.changes <- struct{}{}:
+			default:
+				// a signal is already pending
+			}
+		}
+	}
+}
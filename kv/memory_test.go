@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package kv_test
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/AlphaOne1/templig/kv"
+)
+
+func TestMemory(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]string{"app/id": "9", "app/name": "kvName"}
+	store := kv.NewMemory(values)
+
+	values["app/id"] = "10"
+
+	if value, found, _ := store.Get(t.Context(), "app/id"); !found || value != "9" {
+		t.Errorf("wanted value 9 but got %v (found: %v)", value, found)
+	}
+
+	store.Set("other/name", "otherName")
+	store.Delete("app/name")
+	store.Delete("app/none")
+
+	if _, found, _ := store.Get(t.Context(), "app/name"); found {
+		t.Errorf("did not want deleted key to be found")
+	}
+
+	listed, _ := store.List(t.Context(), "app/")
+
+	if want := map[string]string{"app/id": "9"}; !maps.Equal(listed, want) {
+		t.Errorf("wanted %v but got %v", want, listed)
+	}
+}
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v4"
+)
+
+// Origin describes the location a configuration value was last set from.
+type Origin struct {
+	// Source is the name of the source, that is the file name for files.
+	Source string
+	// Line is the line of the value in the source, after templating took place.
+	Line int
+	// Column is the column of the value in the source, after templating took place.
+	Column int
+	// Secret tells if the value was decrypted or marked as secret, thus always being hidden by [Config.ToSecretsHidden].
+	Secret bool
+}
+
+// String gives the origin in the common `source:line:column` notation.
+func (o Origin) String() string {
+	return fmt.Sprintf("%s:%d:%d", o.Source, o.Line, o.Column)
+}
+
+// collectOrigins records the origin of the given node and all its descendants in the origins map.
+// Paths are constructed from mapping keys and sequence indices, separated by dots.
+func collectOrigins(origins map[string]Origin, node *yaml.Node, path []string, source string) {
+	if node == nil {
+		return
+	}
+
+	if node.Kind == yaml.DocumentNode {
+		for _, v := range node.Content {
+			collectOrigins(origins, v, path, source)
+		}
+
+		return
+	}
+
+	origins[strings.Join(path, ".")] = Origin{
+		Source: source,
+		Line:   node.Line,
+		Column: node.Column,
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			collectOrigins(origins, node.Content[i+1], append(path, node.Content[i].Value), source)
+		}
+	case yaml.SequenceNode:
+		for i, v := range node.Content {
+			collectOrigins(origins, v, append(path, strconv.Itoa(i)), source)
+		}
+	default:
+		// scalars have no descendants, aliases are not followed to not record their targets twice
+	}
+}
+
+// mergeOrigins adds the origins of a configuration overlaid on top of base to the given origins map.
+// As sequences are concatenated by [MergeYAMLNodes], the indices of the overlay are shifted accordingly.
+func mergeOrigins(origins map[string]Origin, base *yaml.Node, overlay map[string]Origin) {
+	for path, origin := range overlay {
+		origins[translatePath(base, path)] = origin
+	}
+}
+
+// translatePath translates a path of an overlay to the path it has after merging it on top of base.
+func translatePath(base *yaml.Node, path string) string {
+	if base == nil || path == "" {
+		return path
+	}
+
+	segments := strings.Split(path, ".")
+	node := base
+
+	for node != nil && node.Kind == yaml.DocumentNode && len(node.Content) == 1 {
+		node = node.Content[0]
+	}
+
+	for i, segment := range segments {
+		for node != nil && node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+
+		if node == nil {
+			break
+		}
+
+		switch node.Kind {
+		case yaml.SequenceNode:
+			if index, err := strconv.Atoi(segment); err == nil {
+				segments[i] = strconv.Itoa(index + len(node.Content))
+			}
+
+			// appended elements are not yet part of base
+			node = nil
+		case yaml.MappingNode:
+			node = mappingValue(node, segment)
+		default:
+			node = nil
+		}
+	}
+
+	return strings.Join(segments, ".")
+}
+
+// mappingValue gives the value node stored under the given key in a mapping node, nil if there is none.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Kind == yaml.ScalarNode && node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// annotateOrigins adds line comments to the given node structure, naming the origin of the values.
+// Scalars carry the comment themselves, for collections it is attached to their key.
+func annotateOrigins(node *yaml.Node, path []string, origins map[string]Origin) {
+	comment := func(path []string) string {
+		if o, ok := origins[strings.Join(path, ".")]; ok {
+			return fmt.Sprintf("from %s:%d", o.Source, o.Line)
+		}
+
+		return ""
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, v := range node.Content {
+			annotateOrigins(v, path, origins)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			valuePath := append(path, node.Content[i].Value)
+
+			if node.Content[i+1].Kind == yaml.ScalarNode {
+				node.Content[i+1].LineComment = comment(valuePath)
+			} else {
+				node.Content[i].LineComment = comment(valuePath)
+				annotateOrigins(node.Content[i+1], valuePath, origins)
+			}
+		}
+	case yaml.SequenceNode:
+		for i, v := range node.Content {
+			elementPath := append(path, strconv.Itoa(i))
+
+			if v.Kind == yaml.ScalarNode {
+				v.LineComment = comment(elementPath)
+			} else {
+				annotateOrigins(v, elementPath, origins)
+			}
+		}
+	default:
+		// scalars are annotated by their parents, aliases are not followed
+	}
+}
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AlphaOne1/templig"
+)
+
+func TestOrigin(t *testing.T) {
+	t.Parallel()
+
+	config, configErr := templig.New[TestConfig](
+		templig.WithValue("pass2", "pass2"),
+		templig.WithFile("testData/test_config_0.yaml"),
+		templig.WithFile("testData/test_config_0_overlay.yaml"))
+
+	if configErr != nil {
+		t.Fatalf("no error expected reading multiple files: %v", configErr)
+	}
+
+	tests := []struct {
+		path  string
+		want  templig.Origin
+		found bool
+	}{
+		{ // 0
+			path:  "id",
+			want:  templig.Origin{Source: "testData/test_config_0.yaml", Line: 4, Column: 5},
+			found: true,
+		},
+		{ // 1
+			path:  "conn.url",
+			want:  templig.Origin{Source: "testData/test_config_0.yaml", Line: 7, Column: 10},
+			found: true,
+		},
+		{ // 2
+			path:  "conn.passes.1",
+			want:  templig.Origin{Source: "testData/test_config_0.yaml", Line: 10, Column: 9},
+			found: true,
+		},
+		{ // 3
+			path:  "conn.passes.2",
+			want:  templig.Origin{Source: "testData/test_config_0_overlay.yaml", Line: 6, Column: 9},
+			found: true,
+		},
+		{ // 4
+			path:  "conn.passes.3",
+			found: false,
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("Origin-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			got, found := config.Origin(test.path)
+
+			if found != test.found {
+				t.Errorf("%v: wanted found %v but got %v", testIndex, test.found, found)
+			}
+
+			if got != test.want {
+				t.Errorf("%v: wanted origin %v but got %v", testIndex, test.want, got)
+			}
+		})
+	}
+}
+
+func TestOriginReader(t *testing.T) {
+	t.Parallel()
+
+	config, configErr := templig.From[TestConfig](
+		strings.NewReader("id: 9"),
+		strings.NewReader("name: Name0"))
+
+	if configErr != nil {
+		t.Fatalf("no error expected reading multiple readers: %v", configErr)
+	}
+
+	if got, _ := config.Origin("name"); got.String() != "reader[1]:1:7" {
+		t.Errorf("wanted origin reader[1]:1:7 but got %v", got)
+	}
+}
+
+func TestToAnnotated(t *testing.T) {
+	t.Parallel()
+
+	config, configErr := templig.New[TestConfig](
+		templig.WithValue("pass2", "pass2"),
+		templig.WithFile("testData/test_config_0.yaml"),
+		templig.WithFile("testData/test_config_0_overlay.yaml"))
+
+	if configErr != nil {
+		t.Fatalf("no error expected reading multiple files: %v", configErr)
+	}
+
+	buf := bytes.Buffer{}
+
+	if err := config.ToAnnotated(&buf); err != nil {
+		t.Errorf("could not write annotated configuration: %v", err)
+	}
+
+	for _, want := range []string{
+		"id: 9 # from testData/test_config_0.yaml:4\n",
+		"conn: # from testData/test_config_0_overlay.yaml:5\n",
+		"- pass2 # from testData/test_config_0_overlay.yaml:6\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("did not find %q in annotated output:\n%v", want, buf.String())
+		}
+	}
+}
+
+func TestToAnnotatedBrokenWriter(t *testing.T) {
+	t.Parallel()
+
+	c, _ := templig.FromFile[TestConfig]("testData/test_config_0.yaml")
+
+	if err := c.ToAnnotated(&BrokenIO{}); err == nil {
+		t.Errorf("writing to broken writer should have returned an error")
+	}
+}
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig
+
+import (
+	"path/filepath"
+)
+
+// WithoutSourceRelativePaths creates an Option that resolves relative paths given to the `read` template function and
+// the `!include` tag relative to the working directory, as in former versions, instead of relative to the directory of
+// the file containing them.
+func WithoutSourceRelativePaths() Option {
+	return func(c configurable) error {
+		return c.disableSourceRelativePaths()
+	}
+}
+
+func (c *Config[T]) disableSourceRelativePaths() error {
+	c.workingDirPaths = true
+
+	return nil
+}
+
+// baseDir gives the directory relative paths in the given source are resolved against. Paths in sources that are
+// not files, or if source-relative paths are disabled, are resolved relative to the working directory or the sandbox
+// root, indicated by an empty base directory.
+func (c *Config[T]) baseDir(s source) string {
+	if c.workingDirPaths || s.fileName == "" {
+		return ""
+	}
+
+	return filepath.Dir(s.fileName)
+}
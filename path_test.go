@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AlphaOne1/templig"
+)
+
+func TestSourceRelativePaths(t *testing.T) {
+	t.Parallel()
+
+	env := templig.WithEnv(map[string]string{"PASS1": "pass1"})
+
+	tests := []struct {
+		options  []templig.Option
+		wantName string
+		wantErr  bool
+	}{
+		{ // 0
+			options:  []templig.Option{env, templig.WithFile("testData/test_config_1.yaml")},
+			wantName: "Name1",
+		},
+		{ // 1
+			options: []templig.Option{
+				env,
+				templig.WithoutSourceRelativePaths(),
+				templig.WithFile("testData/test_config_1.yaml"),
+			},
+			wantErr: true,
+		},
+		{ // 2
+			options:  []templig.Option{templig.WithFile("testData/include/read_secret.yaml")},
+			wantName: "pass0",
+		},
+		{ // 3
+			options: []templig.Option{
+				templig.WithFile("testData/test_config_0.yaml"),
+				templig.WithFile("testData/include/read_secret.yaml"),
+			},
+			wantName: "pass0",
+		},
+		{ // 4
+			options: []templig.Option{
+				templig.WithoutSourceRelativePaths(),
+				templig.WithReader(strings.NewReader(`name: {{ read "testData/secret.txt" }}`)),
+			},
+			wantName: "pass0",
+		},
+		{ // 5
+			options: []templig.Option{
+				templig.WithoutSourceRelativePaths(),
+				templig.WithFile("testData/test_config_include.yaml"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("SourceRelativePaths-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			config, configErr := templig.New[TestConfig](test.options...)
+
+			if test.wantErr {
+				if configErr == nil {
+					t.Errorf("%v: wanted error but got nil", testIndex)
+				}
+
+				return
+			}
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			if config.Get().Name != test.wantName {
+				t.Errorf("%v: wanted name %v but got %v", testIndex, test.wantName, config.Get().Name)
+			}
+		})
+	}
+}
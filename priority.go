@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+)
+
+// Priority defines the precedence of a configuration source. Sources of higher priority are overlaid on top of the
+// ones of lower priority, independent of the order they were added in. Sources of equal priority keep their order.
+type Priority int
+
+// The predefined priorities leave room in between, so own priorities can be placed among them.
+const (
+	// PriorityDefaults is intended for sources containing default values, e.g. provided by libraries.
+	PriorityDefaults Priority = 100
+
+	// PriorityFiles is the priority of all sources not given an explicit priority.
+	PriorityFiles Priority = 200
+
+	// PriorityEnv is intended for sources generated from the environment.
+	PriorityEnv Priority = 300
+
+	// PriorityFlags is intended for sources generated from command line flags.
+	PriorityFlags Priority = 400
+)
+
+// WithPriority creates an Option that assigns the given priority to all sources added by the given options.
+//
+//	templig.New[Config](
+//	    templig.WithFile("my_config.yaml"),
+//	    templig.WithPriority(templig.PriorityDefaults, templig.WithReader(defaults)),
+//	)
+//
+// thus applies `my_config.yaml` on top of the defaults.
+func WithPriority(priority Priority, opts ...Option) Option {
+	return func(c configurable) error {
+		return c.applyWithPriority(priority, opts...)
+	}
+}
+
+func (c *Config[T]) applyWithPriority(priority Priority, opts ...Option) error {
+	oldPriority := c.priority
+	c.priority = priority
+
+	defer func() { c.priority = oldPriority }()
+
+	var errs []error
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// currentPriority gives the priority to assign to newly added sources.
+func (c *Config[T]) currentPriority() Priority {
+	if c.priority == 0 {
+		return PriorityFiles
+	}
+
+	return c.priority
+}
+
+// sortSources sorts the given sources by their priority, keeping the order of sources of equal priority.
+func sortSources(sources []source) {
+	slices.SortStableFunc(sources, func(a, b source) int {
+		return cmp.Compare(a.priority, b.priority)
+	})
+}
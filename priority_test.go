@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AlphaOne1/templig"
+)
+
+func TestPriority(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		options  func() []templig.Option
+		wantID   int
+		wantName string
+		wantErr  bool
+	}{
+		{ // 0
+			options: func() []templig.Option {
+				return []templig.Option{
+					templig.WithReader(strings.NewReader("id: 1\nname: files")),
+					templig.WithPriority(templig.PriorityDefaults,
+						templig.WithReader(strings.NewReader("id: 2\nname: defaults"))),
+				}
+			},
+			wantID:   1,
+			wantName: "files",
+		},
+		{ // 1
+			options: func() []templig.Option {
+				return []templig.Option{
+					templig.WithPriority(templig.PriorityFlags,
+						templig.WithReader(strings.NewReader("name: flags"))),
+					templig.WithPriority(templig.PriorityEnv,
+						templig.WithReader(strings.NewReader("id: 3\nname: env"))),
+					templig.WithReader(strings.NewReader("id: 1\nname: files")),
+				}
+			},
+			wantID:   3,
+			wantName: "flags",
+		},
+		{ // 2
+			options: func() []templig.Option {
+				return []templig.Option{
+					templig.WithPriority(templig.PriorityDefaults,
+						templig.WithReader(strings.NewReader("id: 1\nname: first")),
+						templig.WithReader(strings.NewReader("name: second"))),
+				}
+			},
+			wantID:   1,
+			wantName: "second",
+		},
+		{ // 3
+			options: func() []templig.Option {
+				return []templig.Option{
+					templig.WithPriority(templig.PriorityDefaults,
+						templig.WithReader(),
+						templig.WithFile()),
+				}
+			},
+			wantErr: true,
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("Priority-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			config, configErr := templig.New[TestConfig](test.options()...)
+
+			if test.wantErr {
+				if !errors.Is(configErr, templig.ErrNoConfigReaders) || !errors.Is(configErr, templig.ErrNoConfigPaths) {
+					t.Errorf("%v: wanted errors of all nested options but got %v", testIndex, configErr)
+				}
+
+				return
+			}
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			if config.Get().ID != test.wantID {
+				t.Errorf("%v: wanted ID %v but got %v", testIndex, test.wantID, config.Get().ID)
+			}
+
+			if config.Get().Name != test.wantName {
+				t.Errorf("%v: wanted name %v but got %v", testIndex, test.wantName, config.Get().Name)
+			}
+		})
+	}
+}
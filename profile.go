@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrNoProfiles indicates that no profiles were provided where at least one is required.
+	ErrNoProfiles = errors.New("no profiles given")
+)
+
+// WithProfiles creates an Option that activates the given profiles. Sources registered for a profile using
+// [WithProfileFile] or [WithProfiledFile] are only used if their profile is active.
+func WithProfiles(profiles ...string) Option {
+	return func(c configurable) error {
+		if len(profiles) == 0 {
+			return ErrNoProfiles
+		}
+
+		return c.addProfiles(profiles...)
+	}
+}
+
+// WithProfilesFromEnv creates an Option that activates the profiles given in the environment variable with the
+// given name. Multiple profiles are separated by commas, e.g. `APP_PROFILES=prod,eu`.
+func WithProfilesFromEnv(name string) Option {
+	return func(c configurable) error {
+		return c.addProfileEnv(name)
+	}
+}
+
+// WithProfilesFromArg creates an Option that activates the profiles given in the command line argument with the
+// given name. Multiple profiles are separated by commas, e.g. `--profiles=prod,eu`.
+func WithProfilesFromArg(name string) Option {
+	return func(c configurable) error {
+		return c.addProfileArg(name)
+	}
+}
+
+// WithProfileFile creates an Option to specify file paths as configuration sources that are only used if the given
+// profile is active.
+func WithProfileFile(profile string, fileNames ...string) Option {
+	return func(c configurable) error {
+		if len(fileNames) == 0 {
+			return ErrNoConfigPaths
+		}
+
+		sources := make([]source, len(fileNames))
+
+		for i := range fileNames {
+			sources[i] = source{fileName: fileNames[i], profile: profile}
+		}
+
+		return c.addSources(sources...)
+	}
+}
+
+// WithProfiledFile creates an Option to specify file paths as configuration sources, that are each followed by
+// their profile-specific variants. For every active profile, the variant is constructed by inserting the profile name
+// before the file extension, so `config.yaml` is followed by `config.prod.yaml` if the profile `prod` is active.
+// Variants that do not exist are skipped.
+func WithProfiledFile(fileNames ...string) Option {
+	return func(c configurable) error {
+		if len(fileNames) == 0 {
+			return ErrNoConfigPaths
+		}
+
+		sources := make([]source, len(fileNames))
+
+		for i := range fileNames {
+			sources[i] = source{fileName: fileNames[i], profiled: true}
+		}
+
+		return c.addSources(sources...)
+	}
+}
+
+func (c *Config[T]) addProfiles(profiles ...string) error {
+	c.profiles = append(c.profiles, profiles...)
+
+	return nil
+}
+
+func (c *Config[T]) addProfileEnv(name string) error {
+	c.profileEnvs = append(c.profileEnvs, name)
+
+	return nil
+}
+
+func (c *Config[T]) addProfileArg(name string) error {
+	c.profileArgs = append(c.profileArgs, name)
+
+	return nil
+}
+
+// Profiles gives the active profiles of the configuration, in the order of their activation.
+func (c *Config[T]) Profiles() []string {
+	return slices.Clone(c.activeProfiles())
+}
+
+// activeProfiles gives the explicitly set profiles, followed by the ones from the environment and the command line.
+// Duplicates are removed.
+func (c *Config[T]) activeProfiles() []string {
+	result := make([]string, 0, len(c.profiles))
+	add := func(profiles ...string) {
+		for _, p := range profiles {
+			if p = strings.TrimSpace(p); p != "" && !slices.Contains(result, p) {
+				result = append(result, p)
+			}
+		}
+	}
+
+	add(c.profiles...)
+
+	for _, name := range c.profileEnvs {
+		add(strings.Split(c.getEnv(name), ",")...)
+	}
+
+	for _, name := range c.profileArgs {
+		add(strings.Split(argumentString(c.arguments(), name, c.posixFlags), ",")...)
+	}
+
+	return result
+}
+
+// activeSources gives the sources to be used with the currently active profiles, sorted by their priority.
+// Sources for inactive profiles are left out, profiled sources are followed by their existing variants.
+func (c *Config[T]) activeSources() []source {
+	profiles := c.activeProfiles()
+	result := make([]source, 0, len(c.sources))
+
+	for _, s := range c.sources {
+		if s.profile != "" && !slices.Contains(profiles, s.profile) {
+			continue
+		}
+
+		result = append(result, s)
+
+		if !s.profiled {
+			continue
+		}
+
+		for _, p := range profiles {
+			variant := profileVariant(s.fileName, p)
+
+			if _, err := os.Stat(variant); errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			result = append(result, source{fileName: variant, priority: s.priority, delims: s.delims})
+		}
+	}
+
+	sortSources(result)
+
+	return result
+}
+
+// profileVariant gives the name of the variant of the given file for the given profile.
+func profileVariant(fileName, profile string) string {
+	ext := filepath.Ext(fileName)
+
+	return strings.TrimSuffix(fileName, ext) + "." + profile + ext
+}
@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/AlphaOne1/templig"
+)
+
+func TestProfiles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		options      []templig.Option
+		wantName     string
+		wantPasses   int
+		wantProfiles []string
+	}{
+		{ // 0
+			options: []templig.Option{
+				templig.WithProfiledFile("testData/test_config_0.yaml"),
+			},
+			wantName:     "Name0",
+			wantPasses:   2,
+			wantProfiles: []string{},
+		},
+		{ // 1
+			options: []templig.Option{
+				templig.WithProfiledFile("testData/test_config_0.yaml"),
+				templig.WithProfiles("prod"),
+			},
+			wantName:     "NameProd",
+			wantPasses:   2,
+			wantProfiles: []string{"prod"},
+		},
+		{ // 2
+			options: []templig.Option{
+				templig.WithProfiles("dev"),
+				templig.WithProfiledFile("testData/test_config_0.yaml"),
+			},
+			wantName:     "Name0",
+			wantPasses:   2,
+			wantProfiles: []string{"dev"},
+		},
+		{ // 3
+			options: []templig.Option{
+				templig.WithFile("testData/test_config_0.yaml"),
+				templig.WithProfileFile("pass", "testData/test_config_0_overlay.yaml"),
+				templig.WithProfiles("prod"),
+			},
+			wantName:     "Name0",
+			wantPasses:   2,
+			wantProfiles: []string{"prod"},
+		},
+		{ // 4
+			options: []templig.Option{
+				templig.WithFile("testData/test_config_0.yaml"),
+				templig.WithProfileFile("pass", "testData/test_config_0_overlay.yaml"),
+				templig.WithProfiles("prod", "pass", "prod"),
+			},
+			wantName:     "Name0",
+			wantPasses:   3,
+			wantProfiles: []string{"prod", "pass"},
+		},
+		{ // 5
+			options: []templig.Option{
+				templig.WithArgs([]string{"serve", "--profiles", "prod,pass"}),
+				templig.WithProfilesFromArg("profiles"),
+				templig.WithFile("testData/test_config_0.yaml"),
+				templig.WithProfileFile("pass", "testData/test_config_0_overlay.yaml"),
+			},
+			wantName:     "Name0",
+			wantPasses:   3,
+			wantProfiles: []string{"prod", "pass"},
+		},
+		{ // 6
+			options: []templig.Option{
+				templig.WithEnv(map[string]string{"TEMPLIG_TEST_PROFILES": "prod"}),
+				templig.WithProfilesFromEnv("TEMPLIG_TEST_PROFILES"),
+				templig.WithProfiledFile("testData/test_config_0.yaml"),
+			},
+			wantName:     "NameProd",
+			wantPasses:   2,
+			wantProfiles: []string{"prod"},
+		},
+		{ // 7
+			options: []templig.Option{
+				templig.WithSourceDelims("[[", "]]", templig.WithProfiledFile("testData/test_delims.yaml")),
+				templig.WithProfiles("prod"),
+			},
+			wantName:     "NameProd",
+			wantPasses:   2,
+			wantProfiles: []string{"prod"},
+		},
+		{ // 8
+			options: []templig.Option{
+				templig.WithArgs([]string{"-vp", "prod"}),
+				templig.WithPOSIXFlags(),
+				templig.WithProfilesFromArg("p"),
+				templig.WithProfiledFile("testData/test_config_0.yaml"),
+			},
+			wantName:     "NameProd",
+			wantPasses:   2,
+			wantProfiles: []string{"prod"},
+		},
+		{ // 9
+			options: []templig.Option{
+				templig.WithArgs([]string{"-vp", "prod"}),
+				templig.WithProfilesFromArg("p"),
+				templig.WithProfiledFile("testData/test_config_0.yaml"),
+			},
+			wantName:     "Name0",
+			wantPasses:   2,
+			wantProfiles: []string{},
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("Profiles-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			config, configErr := templig.New[TestConfig](test.options...)
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			if config.Get().Name != test.wantName {
+				t.Errorf("%v: wanted name %v but got %v", testIndex, test.wantName, config.Get().Name)
+			}
+
+			if len(config.Get().Conn.Passes) != test.wantPasses {
+				t.Errorf("%v: wanted %v passes but got %v", testIndex, test.wantPasses, config.Get().Conn.Passes)
+			}
+
+			if !slices.Equal(config.Profiles(), test.wantProfiles) {
+				t.Errorf("%v: wanted profiles %v but got %v", testIndex, test.wantProfiles, config.Profiles())
+			}
+		})
+	}
+}
+
+func TestProfilesFromEnv(t *testing.T) {
+	t.Setenv("TEMPLIG_TEST_PROFILES", " dev, prod ")
+
+	config, configErr := templig.New[TestConfig](
+		templig.WithProfiledFile("testData/test_config_0.yaml"),
+		templig.WithProfilesFromEnv("TEMPLIG_TEST_PROFILES"))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	if config.Get().Name != "NameProd" {
+		t.Errorf("wanted name NameProd but got %v", config.Get().Name)
+	}
+
+	if want := []string{"dev", "prod"}; !slices.Equal(config.Profiles(), want) {
+		t.Errorf("wanted profiles %v but got %v", want, config.Profiles())
+	}
+}
+
+func TestNoProfiles(t *testing.T) {
+	t.Parallel()
+
+	_, err := templig.New[TestConfig](
+		templig.WithFile("testData/test_config_0.yaml"),
+		templig.WithProfiles())
+
+	if !errors.Is(err, templig.ErrNoProfiles) {
+		t.Errorf("activating no profiles should have returned an error")
+	}
+}
+
+func TestOnlyInactiveProfileFiles(t *testing.T) {
+	t.Parallel()
+
+	_, err := templig.New[TestConfig](
+		templig.WithProfileFile("prod", "testData/test_config_0.yaml"))
+
+	if !errors.Is(err, templig.ErrNoConfigPaths) {
+		t.Errorf("having only inactive profile files should have returned an error")
+	}
+}
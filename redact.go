@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+
+	"go.yaml.in/yaml/v4"
+)
+
+// Redacted is the text a [Secret] is replaced with whenever it is printed, logged or encoded.
+const Redacted = "*****"
+
+// Secret holds a secret value, e.g. a password, as a field of a configuration. It is decoded from YAML and JSON like
+// its value, but redacted when printed using fmt, logged using slog or encoded as YAML or JSON, preventing secrets
+// from leaking through accidental logging of the configuration. Its value is only accessible using [Secret.Reveal].
+//
+// As it is redacted when encoded, configurations containing secrets cannot be written completely using [Config.To].
+type Secret[T any] struct {
+	value T
+}
+
+// NewSecret creates a secret holding the given value.
+func NewSecret[T any](value T) Secret[T] {
+	return Secret[T]{value: value}
+}
+
+// Reveal gives the value of the secret.
+func (s Secret[T]) Reveal() T {
+	return s.value
+}
+
+// IsZero tells if the value of the secret is the zero value, so it is left out by the `omitempty` YAML option.
+func (s Secret[T]) IsZero() bool {
+	return reflect.ValueOf(&s.value).Elem().IsZero()
+}
+
+// String gives the redacted text.
+func (s Secret[T]) String() string {
+	return Redacted
+}
+
+// GoString gives the redacted text, also used for the `%#v` verb.
+func (s Secret[T]) GoString() string {
+	return Redacted
+}
+
+// Format writes the redacted text for all verbs, e.g. also for `%d` or `%x`.
+func (s Secret[T]) Format(f fmt.State, _ rune) {
+	_, _ = io.WriteString(f, Redacted)
+}
+
+// LogValue gives the redacted text to be logged using slog.
+func (s Secret[T]) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+// MarshalJSON encodes the redacted text.
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted) //nolint:wrapcheck // encoding a constant string does not fail
+}
+
+// UnmarshalJSON decodes the value of the secret.
+func (s *Secret[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &s.value) //nolint:wrapcheck // keeps the position given by the decoder
+}
+
+// MarshalYAML encodes the redacted text.
+func (s Secret[T]) MarshalYAML() (any, error) {
+	return Redacted, nil
+}
+
+// UnmarshalYAML decodes the value of the secret.
+func (s *Secret[T]) UnmarshalYAML(node *yaml.Node) error {
+	return node.Decode(&s.value) //nolint:wrapcheck // keeps the position given by the decoder
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/AlphaOne1/templig"
+)
+
+type RedactedConfig struct {
+	User     string                   `json:"user" yaml:"user"`
+	Password templig.Secret[string]   `json:"password" yaml:"password"`
+	Pin      templig.Secret[int]      `json:"pin" yaml:"pin"`
+	Keys     templig.Secret[[]string] `json:"keys,omitempty" yaml:"keys,omitempty"`
+}
+
+func TestSecretType(t *testing.T) {
+	t.Parallel()
+
+	config, configErr := templig.New[RedactedConfig](
+		templig.WithReader(strings.NewReader("user: admin\npassword: pass0\npin: 1234")))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	c := config.Get()
+
+	if c.Password.Reveal() != "pass0" || c.Pin.Reveal() != 1234 || c.Keys.Reveal() != nil {
+		t.Errorf("wanted revealed values pass0/1234/nil but got %v/%v/%v",
+			c.Password.Reveal(), c.Pin.Reveal(), c.Keys.Reveal())
+	}
+
+	var logged bytes.Buffer
+
+	slog.New(slog.NewTextHandler(&logged, nil)).Info("config", "password", c.Password)
+
+	jsonText, jsonErr := json.Marshal(c)
+
+	if jsonErr != nil {
+		t.Fatalf("did not want error but got %v", jsonErr)
+	}
+
+	var yamlText bytes.Buffer
+
+	if err := config.To(&yamlText); err != nil {
+		t.Fatalf("did not want error but got %v", err)
+	}
+
+	outputs := []string{
+		fmt.Sprintf("%v", *c),
+		fmt.Sprintf("%+v", *c),
+		fmt.Sprintf("%#v", *c),
+		fmt.Sprintf("%s %d %x %q", c.Password, c.Pin, c.Pin, c.Password),
+		c.Password.String(),
+		c.Password.GoString(),
+		logged.String(),
+		string(jsonText),
+		yamlText.String(),
+	}
+
+	for i, output := range outputs {
+		if strings.Contains(output, "pass0") || strings.Contains(output, "1234") || strings.Contains(output, "4d2") {
+			t.Errorf("%v: wanted secrets redacted but got %v", i, output)
+		}
+
+		if !strings.Contains(output, templig.Redacted) {
+			t.Errorf("%v: wanted %v but got %v", i, templig.Redacted, output)
+		}
+	}
+
+	if strings.Contains(yamlText.String(), "keys") {
+		t.Errorf("wanted empty secret to be left out but got %v", yamlText.String())
+	}
+}
+
+func TestSecretTypeDecode(t *testing.T) {
+	t.Parallel()
+
+	var fromJSON RedactedConfig
+
+	if err := json.Unmarshal([]byte(`{"password": "pass0", "pin": 1234, "keys": ["a", "b"]}`), &fromJSON); err != nil {
+		t.Fatalf("did not want error but got %v", err)
+	}
+
+	if fromJSON.Password.Reveal() != "pass0" || fromJSON.Pin.Reveal() != 1234 || len(fromJSON.Keys.Reveal()) != 2 {
+		t.Errorf("wanted decoded values but got %v/%v/%v",
+			fromJSON.Password.Reveal(), fromJSON.Pin.Reveal(), fromJSON.Keys.Reveal())
+	}
+
+	if err := json.Unmarshal([]byte(`{"pin": "no number"}`), &fromJSON); err == nil {
+		t.Errorf("wanted error decoding invalid JSON value but got nil")
+	}
+
+	var fromYAML RedactedConfig
+
+	if err := yaml.Unmarshal([]byte("pin: no number"), &fromYAML); err == nil {
+		t.Errorf("wanted error decoding invalid YAML value but got nil")
+	}
+
+	if templig.NewSecret("value").Reveal() != "value" || !(templig.Secret[string]{}).IsZero() {
+		t.Errorf("wanted secret created with its value")
+	}
+}
@@ -0,0 +1,249 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v4"
+)
+
+var (
+	// ErrUnresolvedReference indicates that a reference names a path that does not exist in the configuration.
+	ErrUnresolvedReference = errors.New("unresolved reference")
+
+	// ErrReferenceCycle indicates that a value directly or indirectly references itself.
+	ErrReferenceCycle = errors.New("reference cycle detected")
+
+	// ErrReferenceNotScalar indicates that a structure is referenced inside a string, where only scalars can be used.
+	ErrReferenceNotScalar = errors.New("referenced value is not a scalar")
+)
+
+// referenceRE matches references of the form `${path}`, including the escaped form `$${path}`.
+var referenceRE = regexp.MustCompile(`\$?\$\{([^{}]*)\}`)
+
+// WithReferences creates an Option that enables references of the form `${path}` in configuration values. They are
+// resolved against the final configuration, after all overlays took place. A value consisting only of a reference is
+// replaced by the referenced value, including its type or structure. References inside of a string are replaced by
+// the referenced scalar value. The escaped form `$${path}` gives the literal text `${path}`.
+//
+//	database:
+//	  host: db.example.com
+//	  port: 5432
+//	  url:  postgres://${database.host}:${database.port}/app
+func WithReferences() Option {
+	return func(c configurable) error {
+		return c.enableReferences()
+	}
+}
+
+func (c *Config[T]) enableReferences() error {
+	c.references = true
+
+	return nil
+}
+
+// referenceState is the resolution state of a node.
+type referenceState int
+
+const (
+	referenceUnresolved referenceState = iota
+	referenceInProgress
+	referenceResolved
+)
+
+// referenceResolver resolves the references contained in a node structure. Values referencing secret values are
+// marked as secret in its origins.
+type referenceResolver struct {
+	root    *yaml.Node
+	state   map[*yaml.Node]referenceState
+	origins map[string]Origin
+}
+
+// resolveReferences resolves all references contained in the given node structure. The origins of values referencing
+// secret values are marked as secret as well.
+func resolveReferences(node *yaml.Node, origins map[string]Origin) error {
+	root := node
+
+	for root != nil && root.Kind == yaml.DocumentNode && len(root.Content) == 1 {
+		root = root.Content[0]
+	}
+
+	r := referenceResolver{
+		root:    root,
+		state:   make(map[*yaml.Node]referenceState),
+		origins: origins,
+	}
+
+	return r.resolveTree(root, nil)
+}
+
+// resolveTree resolves all references in the given node and its descendants.
+func (r *referenceResolver) resolveTree(node *yaml.Node, path []string) error {
+	if node == nil {
+		return nil
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if err := r.resolveTree(node.Content[i+1], append(path, node.Content[i].Value)); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, v := range node.Content {
+			if err := r.resolveTree(v, append(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		return r.resolveScalar(node, path)
+	default:
+		// aliases point to nodes that are resolved on their own
+	}
+
+	return nil
+}
+
+// resolveScalar resolves the references contained in the given scalar node.
+func (r *referenceResolver) resolveScalar(node *yaml.Node, path []string) error {
+	switch r.state[node] {
+	case referenceResolved:
+		return nil
+	case referenceInProgress:
+		return fmt.Errorf("%w at %v", ErrReferenceCycle, strings.Join(path, "."))
+	default:
+		r.state[node] = referenceInProgress
+	}
+
+	matches := referenceRE.FindAllStringSubmatchIndex(node.Value, -1)
+
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(node.Value) && node.Value[1] == '{' {
+		// the value consists only of a reference, so the referenced node is taken as a whole
+		targetPath := node.Value[matches[0][2]:matches[0][3]]
+		target, err := r.lookup(targetPath)
+
+		if err != nil {
+			return fmt.Errorf("could not resolve %v: %w", strings.Join(path, "."), err)
+		}
+
+		anchor := node.Anchor
+		*node = *target
+		node.Anchor = anchor
+
+		r.copySecrets(targetPath, strings.Join(path, "."))
+	} else if len(matches) > 0 {
+		var resolveErr error
+
+		node.Value = referenceRE.ReplaceAllStringFunc(node.Value, func(reference string) string {
+			if strings.HasPrefix(reference, "$$") {
+				return reference[1:]
+			}
+
+			targetPath := reference[2 : len(reference)-1]
+			target, err := r.lookup(targetPath)
+
+			if err == nil && target.Kind != yaml.ScalarNode {
+				err = fmt.Errorf("%w: %v", ErrReferenceNotScalar, reference)
+			}
+
+			if err != nil {
+				resolveErr = errors.Join(resolveErr, err)
+
+				return reference
+			}
+
+			if r.origins[targetPath].Secret {
+				r.markSecret(strings.Join(path, "."))
+			}
+
+			return target.Value
+		})
+
+		if resolveErr != nil {
+			return fmt.Errorf("could not resolve %v: %w", strings.Join(path, "."), resolveErr)
+		}
+	}
+
+	r.state[node] = referenceResolved
+
+	return nil
+}
+
+// copySecrets marks the values copied from the referenced path to the referencing path as secret, if they are secret
+// at the referenced path.
+func (r *referenceResolver) copySecrets(from, to string) {
+	var secrets []string
+
+	for key, origin := range r.origins {
+		if origin.Secret && (key == from || strings.HasPrefix(key, from+".")) {
+			secrets = append(secrets, to+key[len(from):])
+		}
+	}
+
+	for _, key := range secrets {
+		r.markSecret(key)
+	}
+}
+
+// markSecret marks the value at the given path as secret.
+func (r *referenceResolver) markSecret(path string) {
+	origin := r.origins[path]
+	origin.Secret = true
+	r.origins[path] = origin
+}
+
+// lookup finds the node with the given path and resolves the references it contains.
+func (r *referenceResolver) lookup(path string) (*yaml.Node, error) {
+	node := r.root
+	var segments []string
+
+	if path != "" {
+		segments = strings.Split(path, ".")
+	}
+
+	for _, segment := range segments {
+		for node != nil && node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+
+		if node == nil {
+			break
+		}
+
+		switch node.Kind {
+		case yaml.MappingNode:
+			node = mappingValue(node, segment)
+		case yaml.SequenceNode:
+			index, err := strconv.Atoi(segment)
+
+			if err != nil || index < 0 || index >= len(node.Content) {
+				node = nil
+			} else {
+				node = node.Content[index]
+			}
+		default:
+			node = nil
+		}
+	}
+
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	if node == nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnresolvedReference, path)
+	}
+
+	if err := r.resolveTree(node, segments); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/AlphaOne1/templig"
+)
+
+func TestReferences(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      []string
+		want    string
+		wantErr error
+	}{
+		{ // 0
+			in: []string{`
+host: localhost
+port: 5432
+url:  postgres://${host}:${port}/app`},
+			want: `host: localhost
+port: 5432
+url: postgres://localhost:5432/app
+`,
+		},
+		{ // 1
+			in: []string{`
+a: 1
+b: ${a}
+c: ${b}`},
+			want: `a: 1
+b: 1
+c: 1
+`,
+		},
+		{ // 2
+			in: []string{`
+db:
+  host: localhost
+  port: 5432
+url: postgres://${db.host}:${db.port}/app`, `
+db:
+  host: prod.example.com`},
+			want: `db:
+    host: prod.example.com
+    port: 5432
+url: postgres://prod.example.com:5432/app
+`,
+		},
+		{ // 3
+			in: []string{`
+hosts: [a, b]
+first: ${hosts.1}
+all: ${hosts}
+literal: $${hosts}`},
+			want: `hosts: [a, b]
+first: b
+all: [a, b]
+literal: ${hosts}
+`,
+		},
+		{ // 4
+			in: []string{`
+a: ${b}
+b: ${a}`},
+			wantErr: templig.ErrReferenceCycle,
+		},
+		{ // 5
+			in: []string{`
+a: x${c}x`},
+			wantErr: templig.ErrUnresolvedReference,
+		},
+		{ // 6
+			in: []string{`
+a: [1, 2]
+b: x${a}x`},
+			wantErr: templig.ErrReferenceNotScalar,
+		},
+		{ // 7
+			in: []string{`
+a:
+  b: ${a}`},
+			wantErr: templig.ErrReferenceCycle,
+		},
+		{ // 8
+			in: []string{`
+a: [1, 2]
+b: ${a.2}`, `
+c: 3`},
+			wantErr: templig.ErrUnresolvedReference,
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("References-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			options := []templig.Option{templig.WithReferences()}
+
+			for _, v := range test.in {
+				options = append(options, templig.WithReader(strings.NewReader(v)))
+			}
+
+			config, configErr := templig.New[yaml.Node](options...)
+
+			if test.wantErr != nil {
+				if !errors.Is(configErr, test.wantErr) {
+					t.Errorf("%v: wanted error %v but got %v", testIndex, test.wantErr, configErr)
+				}
+
+				return
+			}
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			buf := bytes.Buffer{}
+
+			if err := config.To(&buf); err != nil {
+				t.Errorf("%v: could not write configuration: %v", testIndex, err)
+			}
+
+			if buf.String() != test.want {
+				t.Errorf("%v: wanted\n%v\nbut got\n%v", testIndex, test.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestReferencesTyped(t *testing.T) {
+	t.Parallel()
+
+	config, configErr := templig.New[TestConfig](
+		templig.WithReferences(),
+		templig.WithReader(strings.NewReader("base: 9\nid: ${base}\nname: Name${base}")))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	if config.Get().ID != 9 || config.Get().Name != "Name9" {
+		t.Errorf("wanted ID 9 and name Name9 but got %v and %v", config.Get().ID, config.Get().Name)
+	}
+}
+
+func TestReferencesDisabled(t *testing.T) {
+	t.Parallel()
+
+	config, configErr := templig.From[TestConfig](strings.NewReader("name: ${id}"))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	if config.Get().Name != "${id}" {
+		t.Errorf("wanted references to be left untouched but got %v", config.Get().Name)
+	}
+}
@@ -0,0 +1,443 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+// ErrSandboxViolation indicates that a template tried to do something not permitted by the sandbox.
+var ErrSandboxViolation = errors.New("sandbox violation")
+
+// Sandbox defines the restrictions applied to templates of untrusted configurations.
+type Sandbox struct {
+	// Funcs is the allow-list of template functions. If it is nil, [DefaultSandboxFuncs] is used.
+	Funcs []string
+
+	// ReadRoot is the directory files may be read from, e.g. using `read` or `!include`. Relative file names are
+	// resolved relative to the directory of the file containing them, only those in reader sources are resolved
+	// relative to ReadRoot. If it is empty, no files can be read at all.
+	ReadRoot string
+
+	// Env is the allow-list of environment variables accessible, e.g. using `env`.
+	Env []string
+
+	// Timeout is the maximum execution time of a template. Zero means no limit. The execution is stopped at the next
+	// function call, loop iteration or template invocation after the timeout, a single function call already running,
+	// e.g. reading a large file, is completed first.
+	Timeout time.Duration
+
+	// MaxOutputSize is the maximum size in bytes of the output of a template. Zero means no limit.
+	MaxOutputSize int
+}
+
+// DefaultSandboxFuncs gives the names of the template functions allowed in a sandbox if not specified otherwise.
+// These are the repeatable functions of sprig, except for the computationally expensive ones and the ones allocating
+// memory proportional to a number given, and the templig functions except for the ones reading command line
+// arguments. Functions accessing files or the environment are restricted by the sandbox.
+func DefaultSandboxFuncs() []string {
+	expensive := []string{
+		"bcrypt",
+		"derivePassword",
+		"genCA",
+		"genCAWithKey",
+		"genPrivateKey",
+		"genSelfSignedCert",
+		"genSelfSignedCertWithKey",
+		"genSignedCert",
+		"genSignedCertWithKey",
+		"htpasswd",
+		"indent",
+		"nindent",
+		"randAlpha",
+		"randAlphaNum",
+		"randAscii",
+		"randBytes",
+		"randNumeric",
+		"repeat",
+		"seq",
+		"until",
+		"untilStep",
+	}
+
+	result := []string{
+		"required", "env", "expandenv", "toYAML", "toYAMLIndent",
+		"read", "readRequired", "readOptional", "fileExists", "readYAML", "readJSON", "readLines",
+		"readDir", "glob", "readAll",
+	}
+
+	for name := range sprig.HermeticTxtFuncMap() {
+		if !slices.Contains(expensive, name) {
+			result = append(result, name)
+		}
+	}
+
+	slices.Sort(result)
+
+	return result
+}
+
+// WithSandbox creates an Option that restricts the templates of the configuration to the given sandbox. It is
+// intended for configurations from untrusted sources, e.g. uploaded by customers.
+func WithSandbox(sandbox Sandbox) Option {
+	return func(c configurable) error {
+		return c.setSandbox(sandbox)
+	}
+}
+
+func (c *Config[T]) setSandbox(sandbox Sandbox) error {
+	if sandbox.Funcs == nil {
+		sandbox.Funcs = DefaultSandboxFuncs()
+	}
+
+	c.sandbox = &sandbox
+
+	return nil
+}
+
+// sandboxCheckFunc is the name of the function checking the execution time of sandboxed templates. It is injected at
+// the start of every loop iteration and template invocation, as these can run arbitrarily long without calling any
+// other function.
+const sandboxCheckFunc = "sandboxCheck"
+
+// deadline gives the point in time the execution of a template starting now has to end. It is zero if there is no
+// time limit.
+func (c *Config[T]) deadline() time.Time {
+	if c.sandbox == nil || c.sandbox.Timeout <= 0 {
+		return time.Time{}
+	}
+
+	return time.Now().Add(c.sandbox.Timeout)
+}
+
+// checkDeadline reports an error if the given deadline is exceeded.
+func checkDeadline(deadline time.Time) error {
+	if !deadline.IsZero() && time.Now().After(deadline) {
+		return fmt.Errorf("%w: execution time exceeded", ErrSandboxViolation)
+	}
+
+	return nil
+}
+
+// sandboxFunctions removes all functions not allowed by the sandbox from the given function map. With a deadline, the
+// remaining functions fail once it is exceeded.
+func (c *Config[T]) sandboxFunctions(funcs template.FuncMap, deadline time.Time) template.FuncMap {
+	if c.sandbox == nil {
+		return funcs
+	}
+
+	for name, f := range funcs {
+		switch {
+		case !slices.Contains(c.sandbox.Funcs, name):
+			delete(funcs, name)
+		case !deadline.IsZero():
+			funcs[name] = withDeadline(f, deadline)
+		default:
+			// allowed without time limit
+		}
+	}
+
+	if !deadline.IsZero() {
+		funcs[sandboxCheckFunc] = func() (string, error) { return "", checkDeadline(deadline) }
+	}
+
+	return funcs
+}
+
+// withDeadline wraps the given template function, so it fails without being called once the deadline is exceeded.
+// Functions without error result get one.
+func withDeadline(f any, deadline time.Time) any {
+	v := reflect.ValueOf(f)
+	errorType := reflect.TypeFor[error]()
+
+	if v.Kind() != reflect.Func || v.Type().NumOut() == 0 || v.Type().NumOut() > 2 {
+		return f
+	}
+
+	in := make([]reflect.Type, v.Type().NumIn())
+
+	for i := range in {
+		in[i] = v.Type().In(i)
+	}
+
+	out := []reflect.Type{v.Type().Out(0), errorType}
+	hasError := v.Type().NumOut() == 2
+
+	wrapped := func(args []reflect.Value) []reflect.Value {
+		if err := checkDeadline(deadline); err != nil {
+			return []reflect.Value{reflect.Zero(out[0]), reflect.ValueOf(&err).Elem()}
+		}
+
+		var results []reflect.Value
+
+		if v.Type().IsVariadic() {
+			results = v.CallSlice(args)
+		} else {
+			results = v.Call(args)
+		}
+
+		if !hasError {
+			results = append(results, reflect.Zero(errorType))
+		}
+
+		return results
+	}
+
+	return reflect.MakeFunc(reflect.FuncOf(in, out, v.Type().IsVariadic()), wrapped).Interface()
+}
+
+// sandboxTemplate checks the given parsed template against the restrictions of the sandbox. Ranges over integer
+// constants are rejected. With a deadline, the check of the execution time is injected into all loops and templates.
+func (c *Config[T]) sandboxTemplate(tmpl *template.Template, deadline time.Time) error {
+	if c.sandbox == nil {
+		return nil
+	}
+
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil {
+			continue
+		}
+
+		if err := sandboxNode(t.Tree, t.Tree.Root, !deadline.IsZero()); err != nil {
+			return err
+		}
+
+		if !deadline.IsZero() {
+			injectCheck(t.Tree, t.Tree.Root, t.Tree.Root.Pos)
+		}
+	}
+
+	return nil
+}
+
+// sandboxNode checks the given node of a template and its descendants against the restrictions of the sandbox,
+// injecting the check of the execution time into loops if requested.
+func sandboxNode(tree *parse.Tree, node parse.Node, inject bool) error {
+	var branch *parse.BranchNode
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+
+		for _, child := range n.Nodes {
+			if err := sandboxNode(tree, child, inject); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case *parse.IfNode:
+		branch = &n.BranchNode
+	case *parse.WithNode:
+		branch = &n.BranchNode
+	case *parse.RangeNode:
+		if isIntegerConstant(n.Pipe) {
+			location, _ := tree.ErrorContext(n)
+
+			return fmt.Errorf("template: %v: %w: range over integer", location, ErrSandboxViolation)
+		}
+
+		if inject {
+			injectCheck(tree, n.List, n.Pos)
+		}
+
+		branch = &n.BranchNode
+	default:
+		return nil
+	}
+
+	return errors.Join(sandboxNode(tree, branch.List, inject), sandboxNode(tree, branch.ElseList, inject))
+}
+
+// isIntegerConstant checks if the given pipeline is a single number.
+func isIntegerConstant(pipe *parse.PipeNode) bool {
+	if pipe == nil || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+
+	_, isNumber := pipe.Cmds[0].Args[0].(*parse.NumberNode)
+
+	return isNumber
+}
+
+// injectCheck prepends a call of the function checking the execution time to the given list.
+func injectCheck(tree *parse.Tree, list *parse.ListNode, pos parse.Pos) {
+	if list == nil {
+		return
+	}
+
+	check := &parse.ActionNode{
+		NodeType: parse.NodeAction,
+		Pos:      pos,
+		Pipe: &parse.PipeNode{
+			NodeType: parse.NodePipe,
+			Pos:      pos,
+			Cmds: []*parse.CommandNode{{
+				NodeType: parse.NodeCommand,
+				Pos:      pos,
+				Args:     []parse.Node{parse.NewIdentifier(sandboxCheckFunc).SetTree(tree).SetPos(pos)},
+			}},
+		},
+	}
+
+	list.Nodes = append([]parse.Node{check}, list.Nodes...)
+}
+
+// resolvePath resolves the given file name relative to the given base directory. If no base directory is given,
+// relative names are resolved relative to the sandbox root or, without sandbox, left relative to the working
+// directory.
+func (c *Config[T]) resolvePath(baseDir, name string) string {
+	if filepath.IsAbs(name) {
+		return filepath.Clean(name)
+	}
+
+	if baseDir == "" && c.sandbox != nil {
+		baseDir = c.sandbox.ReadRoot
+	}
+
+	return filepath.Join(baseDir, name)
+}
+
+// openFile opens the file with the given, already resolved, name. In a sandbox, it can only be opened if it resides
+// inside the sandbox root.
+func (c *Config[T]) openFile(name string) (*os.File, error) {
+	if c.sandbox == nil {
+		return openFile(name)
+	}
+
+	absRoot, relName, err := c.insideReadRoot(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	f, openErr := os.OpenInRoot(absRoot, relName)
+
+	switch {
+	case openErr == nil:
+		return f, nil
+	case errors.Is(openErr, os.ErrNotExist):
+		return nil, fmt.Errorf("could not open %v: %w", name, os.ErrNotExist)
+	default:
+		return nil, fmt.Errorf("%w: could not open %v", ErrSandboxViolation, name)
+	}
+}
+
+// globFiles gives the names of the files matching the given, already resolved, pattern. In a sandbox, the pattern is
+// only matched inside the sandbox root, patterns pointing outside of it are rejected before looking at any file.
+func (c *Config[T]) globFiles(pattern string) ([]string, error) {
+	if c.sandbox == nil {
+		return filepath.Glob(pattern)
+	}
+
+	absRoot, relPattern, err := c.insideReadRoot(pattern)
+
+	if err != nil {
+		return nil, err
+	}
+
+	root, rootErr := os.OpenRoot(absRoot)
+
+	if rootErr != nil {
+		return nil, fmt.Errorf("%w: could not open read root", ErrSandboxViolation)
+	}
+
+	defer func() { _ = root.Close() }()
+
+	matches, globErr := fs.Glob(root.FS(), filepath.ToSlash(relPattern))
+
+	if globErr != nil {
+		return nil, globErr
+	}
+
+	for i := range matches {
+		matches[i] = filepath.Join(absRoot, filepath.FromSlash(matches[i]))
+	}
+
+	return matches, nil
+}
+
+// insideReadRoot gives the absolute sandbox root and the given, already resolved, name relative to it. Names outside
+// the root are rejected without accessing the file system, so that no information about files outside is revealed.
+func (c *Config[T]) insideReadRoot(name string) (string, string, error) {
+	if c.sandbox.ReadRoot == "" {
+		return "", "", fmt.Errorf("%w: no file access permitted", ErrSandboxViolation)
+	}
+
+	absRoot, rootErr := filepath.Abs(c.sandbox.ReadRoot)
+	absName, nameErr := filepath.Abs(name)
+
+	if err := errors.Join(rootErr, nameErr); err != nil {
+		return "", "", fmt.Errorf("could not resolve %v: %w", name, err)
+	}
+
+	relName, relErr := filepath.Rel(absRoot, absName)
+
+	if relErr != nil || relName == ".." || strings.HasPrefix(relName, ".."+string(filepath.Separator)) {
+		return "", "", fmt.Errorf("%w: %v outside of read root", ErrSandboxViolation, name)
+	}
+
+	return absRoot, relName, nil
+}
+
+// lookupEnv gives the value of the environment variable with the given name. In a sandbox, only allow-listed
+// variables can be accessed.
+func (c *Config[T]) lookupEnv(name string) (string, error) {
+	if c.sandbox != nil && !slices.Contains(c.sandbox.Env, name) {
+		return "", fmt.Errorf("%w: environment variable %v not permitted", ErrSandboxViolation, name)
+	}
+
+	return c.getEnv(name), nil
+}
+
+// limitedBuffer is a bytes.Buffer that refuses writes exceeding a maximum size or after a deadline.
+type limitedBuffer struct {
+	bytes.Buffer
+
+	maxSize  int
+	deadline time.Time
+}
+
+// Write appends the given bytes to the buffer, if neither the maximum size nor the deadline is exceeded.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.maxSize > 0 && b.Len()+len(p) > b.maxSize {
+		return 0, fmt.Errorf("%w: output exceeds %d bytes", ErrSandboxViolation, b.maxSize)
+	}
+
+	if !b.deadline.IsZero() && time.Now().After(b.deadline) {
+		return 0, fmt.Errorf("%w: execution time exceeded", ErrSandboxViolation)
+	}
+
+	n, _ := b.Buffer.Write(p)
+
+	return n, nil
+}
+
+// execute executes the given template, honoring the given deadline and the output size limit of the sandbox. The
+// execution is not interrupted from outside, it stops at its next function call, loop iteration, template invocation
+// or write to the buffer after the deadline, so that no execution keeps running after an error was reported.
+func (c *Config[T]) execute(tmpl *template.Template, data any, deadline time.Time) (*bytes.Buffer, error) {
+	b := limitedBuffer{deadline: deadline}
+
+	if c.sandbox != nil {
+		b.maxSize = c.sandbox.MaxOutputSize
+	}
+
+	return &b.Buffer, wrapError("could not execute template", tmpl.Execute(&b, data))
+}
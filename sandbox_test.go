@@ -0,0 +1,233 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlphaOne1/templig"
+)
+
+func TestSandbox(t *testing.T) {
+	t.Setenv("TEMPLIG_SANDBOX_ALLOWED", "allowed")
+	t.Setenv("TEMPLIG_SANDBOX_DENIED", "denied")
+
+	tests := []struct {
+		in       string
+		inFile   string
+		sandbox  templig.Sandbox
+		wantName string
+		wantErr  bool
+	}{
+		{ // 0
+			in:       `name: {{ "name0" | upper }}`,
+			wantName: "NAME0",
+		},
+		{ // 1
+			in:      `name: {{ now }}`,
+			wantErr: true,
+		},
+		{ // 2
+			in:      `name: {{ "name0" | upper }}`,
+			sandbox: templig.Sandbox{Funcs: []string{"lower"}},
+			wantErr: true,
+		},
+		{ // 3
+			in:       `name: {{ read "secret.txt" }}`,
+			sandbox:  templig.Sandbox{ReadRoot: "testData"},
+			wantName: "pass0",
+		},
+		{ // 4
+			in:      `name: {{ read "secret.txt" }}`,
+			wantErr: true,
+		},
+		{ // 5
+			in:      `name: {{ read "../go.mod" }}`,
+			sandbox: templig.Sandbox{ReadRoot: "testData"},
+			wantErr: true,
+		},
+		{ // 6
+			in:       `name: {{ read "does_not_exist.txt" | default "none" }}`,
+			sandbox:  templig.Sandbox{ReadRoot: "testData"},
+			wantName: "none",
+		},
+		{ // 7
+			in:       `name: {{ env "TEMPLIG_SANDBOX_ALLOWED" }}`,
+			sandbox:  templig.Sandbox{Env: []string{"TEMPLIG_SANDBOX_ALLOWED"}},
+			wantName: "allowed",
+		},
+		{ // 8
+			in:      `name: {{ env "TEMPLIG_SANDBOX_DENIED" }}`,
+			sandbox: templig.Sandbox{Env: []string{"TEMPLIG_SANDBOX_ALLOWED"}},
+			wantErr: true,
+		},
+		{ // 9
+			in:       `name: {{ expandenv "${TEMPLIG_SANDBOX_ALLOWED}-x" }}`,
+			sandbox:  templig.Sandbox{Env: []string{"TEMPLIG_SANDBOX_ALLOWED"}},
+			wantName: "allowed-x",
+		},
+		{ // 10
+			in:      `name: {{ expandenv "$TEMPLIG_SANDBOX_DENIED" }}`,
+			sandbox: templig.Sandbox{Env: []string{"TEMPLIG_SANDBOX_ALLOWED"}},
+			wantErr: true,
+		},
+		{ // 11
+			in:      `name: {{ "x" | repeat 100 }}`,
+			sandbox: templig.Sandbox{Funcs: []string{"repeat"}, MaxOutputSize: 50},
+			wantErr: true,
+		},
+		{ // 12
+			in:      `name: {{ range $i := until 10000 }}{{ range $j := until 1000 }}x{{ end }}{{ end }}`,
+			sandbox: templig.Sandbox{Funcs: []string{"until"}, Timeout: 10 * time.Millisecond},
+			wantErr: true,
+		},
+		{ // 13
+			in:       `name: {{ "x" | repeat 10 }}`,
+			sandbox:  templig.Sandbox{Funcs: []string{"repeat"}, Timeout: time.Second, MaxOutputSize: 50},
+			wantName: "xxxxxxxxxx",
+		},
+		{ // 14
+			in:      `conn: !include include/conn.yaml`,
+			wantErr: true,
+		},
+		{ // 15
+			in:      `conn: !include include/conn.yaml`,
+			sandbox: templig.Sandbox{ReadRoot: "testData"},
+		},
+		{ // 16
+			inFile:  "testData/test_config_include.yaml",
+			sandbox: templig.Sandbox{ReadRoot: "testData/include"},
+		},
+		{ // 17
+			inFile:  "testData/test_config_include.yaml",
+			sandbox: templig.Sandbox{ReadRoot: "testData/include/none"},
+			wantErr: true,
+		},
+		{ // 18
+			in:      `name: {{ range 100000000000 }}{{ end }}`,
+			wantErr: true,
+		},
+		{ // 19
+			in:       `name: {{ range $i := until 3 }}x{{ else }}none{{ end }}`,
+			sandbox:  templig.Sandbox{Funcs: []string{"until"}, Timeout: time.Second},
+			wantName: "xxx",
+		},
+		{ // 20
+			in:       `{{ define "t" }}{{ if true }}y{{ end }}{{ end }}name: {{ template "t" }}`,
+			sandbox:  templig.Sandbox{Timeout: time.Second},
+			wantName: "y",
+		},
+		{ // 21
+			in:      `{{ $x := repeat 300000000 "xx" }}name: {{ len $x }}`,
+			sandbox: templig.Sandbox{Timeout: 50 * time.Millisecond, MaxOutputSize: 1024},
+			wantErr: true,
+		},
+		{ // 22
+			in:      `name: {{ len (until 20000000) }}`,
+			sandbox: templig.Sandbox{Timeout: 50 * time.Millisecond, MaxOutputSize: 1024},
+			wantErr: true,
+		},
+		{ // 23
+			in:      `name: {{ indent 300000000 "x" }}`,
+			sandbox: templig.Sandbox{Timeout: 50 * time.Millisecond, MaxOutputSize: 1024},
+			wantErr: true,
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("Sandbox-%d", testIndex), func(t *testing.T) {
+			options := []templig.Option{templig.WithSandbox(test.sandbox)}
+
+			if test.inFile != "" {
+				options = append(options, templig.WithFile(test.inFile))
+			} else {
+				options = append(options, templig.WithReader(strings.NewReader(test.in)))
+			}
+
+			config, configErr := templig.New[TestConfig](options...)
+
+			if test.wantErr {
+				if configErr == nil {
+					t.Errorf("%v: wanted error but got nil", testIndex)
+				}
+
+				return
+			}
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			if config.Get().Name != test.wantName && test.wantName != "" {
+				t.Errorf("%v: wanted name %v but got %v", testIndex, test.wantName, config.Get().Name)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // counts the running goroutines
+func TestSandboxTimeoutStops(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	for _, in := range []string{
+		`{{ $n := 100000000000 }}name: {{ range $n }}{{ end }}`,
+		`{{ define "t" }}{{ template "t" }}{{ template "t" }}{{ end }}name: {{ template "t" }}`,
+	} {
+		_, err := templig.New[TestConfig](
+			templig.WithSandbox(templig.Sandbox{Timeout: 10 * time.Millisecond}),
+			templig.WithReader(strings.NewReader(in)))
+
+		if !errors.Is(err, templig.ErrSandboxViolation) {
+			t.Errorf("wanted sandbox violation but got %v", err)
+		}
+	}
+
+	for deadline := time.Now().Add(2 * time.Second); runtime.NumGoroutine() > before; {
+		if time.Now().After(deadline) {
+			t.Fatalf("wanted template executions to stop, but %v goroutines are still running",
+				runtime.NumGoroutine()-before)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSandboxViolationError(t *testing.T) {
+	t.Parallel()
+
+	_, err := templig.New[TestConfig](
+		templig.WithSandbox(templig.Sandbox{}),
+		templig.WithReader(strings.NewReader(`id: 9`)),
+		templig.WithReader(strings.NewReader(`name: {{ read "testData/secret.txt" }}`)))
+
+	if !errors.Is(err, templig.ErrSandboxViolation) {
+		t.Errorf("wanted sandbox violation but got %v", err)
+	}
+}
+
+func TestDefaultSandboxFuncs(t *testing.T) {
+	t.Parallel()
+
+	funcs := templig.DefaultSandboxFuncs()
+
+	for _, want := range []string{"required", "read", "env", "upper", "toJson"} {
+		if !slices.Contains(funcs, want) {
+			t.Errorf("wanted %v to be allowed by default", want)
+		}
+	}
+
+	for _, notWant := range []string{
+		"now", "genPrivateKey", "getHostByName", "arg", "repeat", "until", "untilStep", "seq", "indent",
+	} {
+		if slices.Contains(funcs, notWant) {
+			t.Errorf("wanted %v not to be allowed by default", notWant)
+		}
+	}
+}
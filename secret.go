@@ -0,0 +1,241 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownSecretScheme indicates that no resolver is registered for the scheme of a secret URI.
+var ErrUnknownSecretScheme = errors.New("unknown secret scheme")
+
+// ErrSecretNotFound indicates that the secret addressed by a URI does not exist.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// ErrNoSecretResolver indicates that no secret resolver function was given.
+var ErrNoSecretResolver = errors.New("no secret resolver given")
+
+// SecretResolver resolves the secret addressed by the given URI, e.g. `vault://kv/db#password`, to its value.
+type SecretResolver func(uri *url.URL) (string, error)
+
+// secretResolvers holds the globally registered secret resolvers by their scheme.
+var secretResolvers = struct { //nolint:gochecknoglobals
+	sync.RWMutex
+
+	resolvers map[string]SecretResolver
+}{
+	resolvers: make(map[string]SecretResolver),
+}
+
+// RegisterSecretResolver registers the resolver for secret URIs with the given scheme, used by the `secret` template
+// function of all configurations. It replaces a previously registered resolver for the same scheme, also the built-in
+// ones for `file` and `env`. A nil resolver removes the registration. In contrast to [TemplateFunctions], it is safe
+// to be called concurrently. To register a resolver for a single configuration only, use [WithSecretResolver].
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	secretResolvers.Lock()
+	defer secretResolvers.Unlock()
+
+	if resolver == nil {
+		delete(secretResolvers.resolvers, strings.ToLower(scheme))
+	} else {
+		secretResolvers.resolvers[strings.ToLower(scheme)] = resolver
+	}
+}
+
+// registeredSecretResolver gives the globally registered resolver for the given scheme.
+func registeredSecretResolver(scheme string) (SecretResolver, bool) {
+	secretResolvers.RLock()
+	defer secretResolvers.RUnlock()
+
+	resolver, found := secretResolvers.resolvers[scheme]
+
+	return resolver, found
+}
+
+// WithSecretResolver creates an Option that registers the resolver for secret URIs with the given scheme for the
+// configuration only. It takes precedence over resolvers registered using [RegisterSecretResolver].
+func WithSecretResolver(scheme string, resolver SecretResolver) Option {
+	return func(c configurable) error {
+		if resolver == nil {
+			return ErrNoSecretResolver
+		}
+
+		return c.addSecretResolver(strings.ToLower(scheme), resolver)
+	}
+}
+
+func (c *Config[T]) addSecretResolver(scheme string, resolver SecretResolver) error {
+	if c.secretResolvers == nil {
+		c.secretResolvers = make(map[string]SecretResolver)
+	}
+
+	c.secretResolvers[scheme] = resolver
+
+	return nil
+}
+
+// withSecretCache creates an Option that sets the cache of resolved secrets. It is used to share the cache with the
+// configurations created during overlays.
+func withSecretCache(cache *secretCache) Option {
+	return func(c configurable) error {
+		return c.setSecretCache(cache)
+	}
+}
+
+func (c *Config[T]) setSecretCache(cache *secretCache) error {
+	c.secretCache = cache
+
+	return nil
+}
+
+// secretCache holds the secrets already resolved by their location, so each secret is only resolved once per load.
+type secretCache struct {
+	sync.Mutex
+
+	values map[string]string
+}
+
+// secretAccess provides the `secret` template function, resolving secret URIs using the registered resolvers and the
+// built-in ones for files and environment variables.
+type secretAccess struct {
+	files     fileAccess
+	lookupEnv func(name string) (string, error)
+	resolvers map[string]SecretResolver
+	cache     *secretCache
+}
+
+// processSecrets gives the secret access of the process, using the files and environment of the process.
+func processSecrets() secretAccess {
+	return secretAccess{
+		files: processFiles(),
+		lookupEnv: func(name string) (string, error) {
+			return os.Getenv(name), nil
+		},
+	}
+}
+
+// secretAccess gives the secret access of that specific instance, resolving relative file names against the given
+// base directory.
+func (c *Config[T]) secretAccess(baseDir string) secretAccess {
+	return secretAccess{
+		files:     c.fileAccess(baseDir),
+		lookupEnv: c.lookupEnv,
+		resolvers: c.secretResolvers,
+		cache:     c.secretCache,
+	}
+}
+
+// resolve is the `secret` template function, giving the value of the secret addressed by the given URI.
+func (a secretAccess) resolve(uri string) (string, error) {
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return "", fmt.Errorf("could not resolve secret: %w", err)
+	}
+
+	key := a.cacheKey(u)
+
+	if a.cache != nil {
+		a.cache.Lock()
+		defer a.cache.Unlock()
+
+		if value, found := a.cache.values[key]; found {
+			return value, nil
+		}
+	}
+
+	value, err := a.resolver(strings.ToLower(u.Scheme))(u)
+
+	if err != nil {
+		return "", fmt.Errorf("could not resolve secret %v: %w", u.Redacted(), err)
+	}
+
+	if a.cache != nil {
+		if a.cache.values == nil {
+			a.cache.values = make(map[string]string)
+		}
+
+		a.cache.values[key] = value
+	}
+
+	return value, nil
+}
+
+// cacheKey gives the key of the secret addressed by the given URI in the cache. As relative file names are resolved
+// against the directory of the source containing them, `file` URIs are identified by the resolved file name.
+func (a secretAccess) cacheKey(uri *url.URL) string {
+	if !strings.EqualFold(uri.Scheme, "file") {
+		return uri.String()
+	}
+
+	return "file:" + a.files.resolve(secretFileName(uri))
+}
+
+// resolver gives the resolver for the given scheme. Resolvers of the instance take precedence over the registered
+// ones, that take precedence over the built-in ones.
+func (a secretAccess) resolver(scheme string) SecretResolver {
+	if resolver, found := a.resolvers[scheme]; found {
+		return resolver
+	}
+
+	if resolver, found := registeredSecretResolver(scheme); found {
+		return resolver
+	}
+
+	switch scheme {
+	case "file":
+		return a.fileSecret
+	case "env":
+		return a.envSecret
+	default:
+		return func(_ *url.URL) (string, error) {
+			return "", fmt.Errorf("%w: %q", ErrUnknownSecretScheme, scheme)
+		}
+	}
+}
+
+// fileSecret resolves `file:///abs/path` or `file:relative/path` URIs to the content of the file, without trailing
+// line breaks.
+func (a secretAccess) fileSecret(uri *url.URL) (string, error) {
+	content, err := a.files.readString(secretFileName(uri))
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("%w: %w", ErrSecretNotFound, err)
+	}
+
+	return strings.TrimRight(content, "\r\n"), err
+}
+
+// secretFileName gives the file name of the given `file` URI.
+func secretFileName(uri *url.URL) string {
+	if uri.Opaque != "" {
+		return uri.Opaque
+	}
+
+	return uri.Path
+}
+
+// envSecret resolves `env:NAME` or `env://NAME` URIs to the value of the environment variable. Unset or empty
+// variables are reported as errors.
+func (a secretAccess) envSecret(uri *url.URL) (string, error) {
+	name := uri.Host
+
+	if uri.Opaque != "" {
+		name = uri.Opaque
+	}
+
+	value, err := a.lookupEnv(name)
+
+	if err == nil && value == "" {
+		err = fmt.Errorf("%w: environment variable %v not set", ErrSecretNotFound, name)
+	}
+
+	return value, err
+}
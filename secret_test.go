@@ -0,0 +1,214 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig_test
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/AlphaOne1/templig"
+)
+
+var errSecretTest = errors.New("secret test error")
+
+func memorySecrets(secrets map[string]string) templig.SecretResolver {
+	return func(uri *url.URL) (string, error) {
+		if value, found := secrets[uri.Host+uri.Path+"#"+uri.Fragment]; found {
+			return value, nil
+		}
+
+		return "", errSecretTest
+	}
+}
+
+func TestSecret(t *testing.T) {
+	t.Parallel()
+
+	templig.RegisterSecretResolver("templig-test", memorySecrets(map[string]string{"kv/db#password": "globalPass"}))
+
+	absSecret, absErr := filepath.Abs("testData/secret.txt")
+
+	if absErr != nil {
+		t.Fatalf("could not get absolute path: %v", absErr)
+	}
+
+	mem := templig.WithSecretResolver("mem", memorySecrets(map[string]string{"kv/db#password": "memPass"}))
+
+	tests := []struct {
+		in          []string
+		options     []templig.Option
+		wantName    string
+		wantErrIs   error
+		wantErrText string
+	}{
+		{ // 0
+			in:       []string{`name: {{ secret "mem://kv/db#password" }}`},
+			options:  []templig.Option{mem},
+			wantName: "memPass",
+		},
+		{ // 1
+			in:       []string{`id: 9`, `name: {{ secret "templig-test://kv/db#password" }}`},
+			wantName: "globalPass",
+		},
+		{ // 2
+			in: []string{`name: {{ secret "templig-test://kv/db#password" }}`},
+			options: []templig.Option{
+				templig.WithSecretResolver("TEMPLIG-TEST", memorySecrets(map[string]string{"kv/db#password": "own"})),
+			},
+			wantName: "own",
+		},
+		{ // 3
+			in:       []string{`name: {{ secret "file://` + filepath.ToSlash(absSecret) + `" }}`},
+			wantName: "pass0",
+		},
+		{ // 4
+			in:       []string{`name: {{ secret "file:testData/secret.txt" }}`},
+			wantName: "pass0",
+		},
+		{ // 5
+			in:       []string{`name: {{ secret "env:TEMPLIG_TEST_SECRET" }}-{{ secret "env://TEMPLIG_TEST_SECRET" }}`},
+			options:  []templig.Option{templig.WithEnv(map[string]string{"TEMPLIG_TEST_SECRET": "envPass"})},
+			wantName: "envPass-envPass",
+		},
+		{ // 6
+			in:        []string{`name: {{ secret "env:TEMPLIG_TEST_SECRET" }}`},
+			options:   []templig.Option{templig.WithEnv(map[string]string{})},
+			wantErrIs: templig.ErrSecretNotFound,
+		},
+		{ // 7
+			in:        []string{`name: {{ secret "unknown://kv/db" }}`},
+			wantErrIs: templig.ErrUnknownSecretScheme,
+		},
+		{ // 8
+			in:          []string{`name: {{ secret "mem://kv/other#password" }}`},
+			options:     []templig.Option{mem},
+			wantErrIs:   errSecretTest,
+			wantErrText: "could not resolve secret mem://kv/other#password",
+		},
+		{ // 9
+			in:        []string{`name: {{ secret "file:testData/does_not_exist.txt" }}`},
+			wantErrIs: templig.ErrSecretNotFound,
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("Secret-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			options := test.options
+
+			for _, v := range test.in {
+				options = append(options, templig.WithReader(strings.NewReader(v)))
+			}
+
+			config, configErr := templig.New[TestConfig](options...)
+
+			if test.wantErrIs != nil || test.wantErrText != "" {
+				if configErr == nil {
+					t.Fatalf("%v: wanted error but got nil", testIndex)
+				}
+
+				if test.wantErrIs != nil && !errors.Is(configErr, test.wantErrIs) {
+					t.Errorf("%v: wanted error %v but got %v", testIndex, test.wantErrIs, configErr)
+				}
+
+				if !strings.Contains(configErr.Error(), test.wantErrText) {
+					t.Errorf("%v: wanted error containing %q but got %v", testIndex, test.wantErrText, configErr)
+				}
+
+				return
+			}
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			if config.Get().Name != test.wantName {
+				t.Errorf("%v: wanted name %v but got %v", testIndex, test.wantName, config.Get().Name)
+			}
+		})
+	}
+}
+
+func TestSecretCache(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	config, configErr := templig.New[TestConfig](
+		templig.WithSecretResolver("count", func(_ *url.URL) (string, error) {
+			calls.Add(1)
+
+			return "counted", nil
+		}),
+		templig.WithReader(strings.NewReader(`name: {{ secret "count://a" }}`)),
+		templig.WithReader(strings.NewReader(`name: {{ secret "count://a" }}{{ secret "count://a" }}`)),
+	)
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	if config.Get().Name != "countedcounted" {
+		t.Errorf("wanted name countedcounted but got %v", config.Get().Name)
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("wanted the secret to be resolved once but got %v calls", calls.Load())
+	}
+}
+
+func TestNoSecretResolver(t *testing.T) {
+	t.Parallel()
+
+	_, err := templig.New[TestConfig](
+		templig.WithFile("testData/test_config_0.yaml"),
+		templig.WithSecretResolver("mem", nil))
+
+	if !errors.Is(err, templig.ErrNoSecretResolver) {
+		t.Errorf("giving no secret resolver should have returned an error")
+	}
+}
+
+func TestSecretCacheRelativeFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"a/db":     "AAA",
+		"a/c.yaml": `name: {{ secret "file:db" }}`,
+		"b/db":     "BBB",
+		"b/c.yaml": `name: {{ secret "file:db" }}`,
+	} {
+		fileName := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(fileName), 0o700); err != nil {
+			t.Fatalf("could not create directory: %v", err)
+		}
+
+		if err := os.WriteFile(fileName, []byte(content), 0o600); err != nil {
+			t.Fatalf("could not write %v: %v", name, err)
+		}
+	}
+
+	config, configErr := templig.New[TestConfig](
+		templig.WithFile(filepath.Join(dir, "a", "c.yaml")),
+		templig.WithFile(filepath.Join(dir, "b", "c.yaml")),
+	)
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	if config.Get().Name != "BBB" {
+		t.Errorf("wanted the secret of the second directory BBB but got %v", config.Get().Name)
+	}
+}
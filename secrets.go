@@ -4,8 +4,12 @@
 package templig
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
+	"reflect"
 	"regexp"
+	"slices"
 	"strings"
 
 	"go.yaml.in/yaml/v4"
@@ -29,7 +33,9 @@ type secretWorkItem struct {
 // Secrets are identified using the given `secretRE` parameter. If that parameter is nil, [SecretDefaultRE] is used
 // instead to construct a new regexp to prevent silent complete failures to hide secrets.
 // Depending on the parameter `hideStructure`, the structure of the secret is hidden too (`true`) or visible (`false`).
-func HideSecrets(node *yaml.Node, hideStructure bool, secretRE *regexp.Regexp) {
+// Values not identified as secret by their key are inspected by the given detectors, that mask the secret parts they
+// find, e.g. using [DefaultSecretDetectors].
+func HideSecrets(node *yaml.Node, hideStructure bool, secretRE *regexp.Regexp, detectors ...SecretDetector) {
 	// initialWorkQueueDepth is an assumption about the maximum depth of the YAML document structure. It will not limit
 	// the real depth, but should, for average use cases of templig, be enough.
 	const initialWorkQueueDepth = 20
@@ -61,6 +67,7 @@ func HideSecrets(node *yaml.Node, hideStructure bool, secretRE *regexp.Regexp) {
 		if currentWork.secret {
 			newWork = hideAll(currentWork.node, hideStructure)
 		} else {
+			detectSecrets(currentWork.node, detectors)
 			newWork = hideSecrets(currentWork.node, secretRE)
 		}
 
@@ -158,3 +165,119 @@ func hideAll(node *yaml.Node, hideStructure bool) []secretWorkItem {
 
 	return nil
 }
+
+// SecretStructTag is the name of the struct tag marking fields as secret, e.g. `templig:"secret"`. Values of such
+// fields are hidden by [Config.ToSecretsHidden] and [Config.ToSecretsHiddenStructured] regardless of their key.
+const SecretStructTag = "templig"
+
+// structField describes a field of a struct by its YAML key.
+type structField struct {
+	typ    reflect.Type
+	secret bool
+}
+
+// structFields gives the fields of the given struct type by their YAML keys, following the naming rules of the YAML
+// library. Fields of inlined structs are included, for an inlined map its element type is returned.
+func structFields(t reflect.Type) (map[string]structField, reflect.Type) {
+	result := make(map[string]structField, t.NumField())
+
+	var inlineMap reflect.Type
+
+	for field := range t.Fields() {
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+
+		if name == "-" {
+			continue
+		}
+
+		if slices.Contains(strings.Split(options, ","), "inline") {
+			fieldType := field.Type
+
+			for fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+
+			switch fieldType.Kind() {
+			case reflect.Struct:
+				inlined, inlinedMap := structFields(fieldType)
+				maps.Insert(result, maps.All(inlined))
+				inlineMap = cmp.Or(inlineMap, inlinedMap)
+			case reflect.Map:
+				inlineMap = fieldType.Elem()
+			default:
+				// not supported by the YAML library
+			}
+
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		result[name] = structField{
+			typ:    field.Type,
+			secret: slices.Contains(strings.Split(field.Tag.Get(SecretStructTag), ","), "secret"),
+		}
+	}
+
+	return result, inlineMap
+}
+
+// hideTaggedSecrets hides the values of the fields marked secret using [SecretStructTag] in the given node structure,
+// that was encoded from a value of the given type. Depending on the parameter `hideStructure`, the structure of the
+// secret is hidden too (`true`) or visible (`false`).
+func hideTaggedSecrets(node *yaml.Node, t reflect.Type, hideStructure bool) {
+	if node == nil || t == nil {
+		return
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case node.Kind == yaml.DocumentNode:
+		for _, v := range node.Content {
+			hideTaggedSecrets(v, t, hideStructure)
+		}
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Struct:
+		fields, inlineMap := structFields(t)
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			field, found := fields[node.Content[i].Value]
+
+			switch {
+			case !found:
+				hideTaggedSecrets(node.Content[i+1], inlineMap, hideStructure)
+			case field.secret:
+				hideNode(node.Content[i+1], hideStructure)
+			default:
+				hideTaggedSecrets(node.Content[i+1], field.typ, hideStructure)
+			}
+		}
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Map:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			hideTaggedSecrets(node.Content[i+1], t.Elem(), hideStructure)
+		}
+	case node.Kind == yaml.SequenceNode && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array):
+		for _, v := range node.Content {
+			hideTaggedSecrets(v, t.Elem(), hideStructure)
+		}
+	default:
+		// scalars are only hidden as field values, other types cannot contain tagged fields
+	}
+}
+
+// hideNode hides the given node completely.
+func hideNode(node *yaml.Node, hideStructure bool) {
+	workQueue := []secretWorkItem{{node: node, secret: true}}
+
+	for len(workQueue) > 0 {
+		workQueue = append(workQueue[1:], hideAll(workQueue[0].node, hideStructure)...)
+	}
+}
@@ -337,3 +337,92 @@ pass: *ref
 		t.Errorf("unexpected output:\n%v\nwanted:\n%v", buf.String(), want)
 	}
 }
+
+type TaggedDB struct {
+	DSN     string   `templig:"secret" yaml:"dsn"`
+	Servers []string `templig:"secret" yaml:"servers"`
+	Pool    int      `yaml:"pool"`
+}
+
+type TaggedCommon struct {
+	Token string `templig:"secret" yaml:"token"`
+}
+
+type TaggedConfig struct {
+	TaggedCommon `yaml:",inline"`
+
+	KeyboardLayout string              `yaml:"keyboard_layout"`
+	DB             *TaggedDB           `yaml:"db"`
+	Replicas       []TaggedDB          `yaml:"replicas"`
+	Tenants        map[string]TaggedDB `yaml:"tenants"`
+	Plain          string
+	Extra          map[string]string `templig:"secret"`
+}
+
+func TestHideTaggedSecrets(t *testing.T) {
+	t.Parallel()
+
+	input := `
+token: tok0
+keyboard_layout: de
+db: { dsn: "postgres://u:p@db", servers: [a0, b0], pool: 5 }
+replicas: [ { dsn: replica0, pool: 6 } ]
+tenants: { t0: { dsn: tenant0, pool: 7 } }
+plain: visible
+extra: { e0: extra0 }
+`
+
+	tests := []struct {
+		hide        func(c *templig.Config[TaggedConfig], b *bytes.Buffer) error
+		wantContain []string
+	}{
+		{ // 0
+			hide: func(c *templig.Config[TaggedConfig], b *bytes.Buffer) error { return c.ToSecretsHidden(b) },
+			wantContain: []string{
+				"token: '****'", "dsn: '*****************'", "servers: '*'", "pool: 5", "dsn: '********'", "pool: 6",
+				"dsn: '*******'", "pool: 7", "plain: visible", "extra: '*'",
+			},
+		},
+		{ // 1
+			hide: func(c *templig.Config[TaggedConfig], b *bytes.Buffer) error { return c.ToSecretsHiddenStructured(b) },
+			wantContain: []string{
+				"token: '****'", "servers:\n", "- '**'", "pool: 5", "plain: visible", "'**': '******'",
+			},
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("HideTaggedSecrets-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			config, configErr := templig.New[TaggedConfig](templig.WithReader(strings.NewReader(input)))
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			var b bytes.Buffer
+
+			if err := test.hide(config, &b); err != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, err)
+			}
+
+			for _, secret := range []string{"tok0", "postgres", "a0", "replica0", "tenant0", "extra0"} {
+				if strings.Contains(b.String(), secret) {
+					t.Errorf("%v: wanted %v to be hidden but got\n%v", testIndex, secret, b.String())
+				}
+			}
+
+			// the regular expression still applies
+			if strings.Contains(b.String(), "keyboard_layout: de") {
+				t.Errorf("%v: wanted keyboard_layout to be hidden but got\n%v", testIndex, b.String())
+			}
+
+			for _, want := range test.wantContain {
+				if !strings.Contains(b.String(), want) {
+					t.Errorf("%v: wanted output containing %q but got\n%v", testIndex, want, b.String())
+				}
+			}
+		})
+	}
+}
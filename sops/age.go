@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package sops
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+var (
+	// ErrInvalidIdentity indicates that an age identity is not of the form `AGE-SECRET-KEY-1...`.
+	ErrInvalidIdentity = errors.New("invalid age identity")
+
+	// ErrInvalidAgeFile indicates that an encrypted data key is not a valid age file.
+	ErrInvalidAgeFile = errors.New("invalid age file")
+)
+
+// parseIdentity parses a native age identity, e.g. an X25519 identity given as `AGE-SECRET-KEY-1...`.
+func parseIdentity(text string) (age.Identity, error) {
+	identities, err := age.ParseIdentities(strings.NewReader(text))
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIdentity, err)
+	}
+
+	if len(identities) != 1 {
+		return nil, fmt.Errorf("%w: wanted a single identity", ErrInvalidIdentity)
+	}
+
+	return identities[0], nil
+}
+
+// decryptAge decrypts the given armored age file using the first matching identity.
+func decryptAge(armored string, identities []age.Identity) ([]byte, error) {
+	r, err := age.Decrypt(armor.NewReader(strings.NewReader(armored)), identities...)
+
+	if noMatch := (*age.NoIdentityMatchError)(nil); errors.As(err, &noMatch) {
+		return nil, ErrNoIdentity
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAgeFile, err)
+	}
+
+	result, err := io.ReadAll(r)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAgeFile, err)
+	}
+
+	return result, nil
+}
+
+// ParseIdentities reads age identities from the given reader in the format of age key files, one identity per line.
+// Empty lines and comments starting with `#` are ignored.
+func ParseIdentities(r io.Reader) ([]string, error) {
+	var result []string
+
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if _, err := parseIdentity(line); err != nil {
+			return nil, err
+		}
+
+		result = append(result, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read identities: %w", err)
+	}
+
+	return result, nil
+}
+
+// DefaultIdentities gives the age identities found in the locations also used by SOPS: the `SOPS_AGE_KEY` environment
+// variable, the file named by the `SOPS_AGE_KEY_FILE` environment variable and the `sops/age/keys.txt` file in the
+// user configuration directory. Missing files are ignored.
+func DefaultIdentities() ([]string, error) {
+	result, err := ParseIdentities(strings.NewReader(os.Getenv("SOPS_AGE_KEY")))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+
+	if keyFile := os.Getenv("SOPS_AGE_KEY_FILE"); keyFile != "" {
+		files = append(files, keyFile)
+	}
+
+	if configDir, dirErr := os.UserConfigDir(); dirErr == nil {
+		files = append(files, filepath.Join(configDir, "sops", "age", "keys.txt"))
+	}
+
+	for _, fileName := range files {
+		identities, fileErr := readIdentities(fileName)
+
+		if fileErr != nil {
+			return nil, fileErr
+		}
+
+		result = append(result, identities...)
+	}
+
+	return result, nil
+}
+
+// readIdentities reads the age identities from the given file, giving none if it does not exist.
+func readIdentities(fileName string) ([]string, error) {
+	f, err := os.Open(filepath.Clean(fileName))
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not open %v: %w", fileName, err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	return ParseIdentities(f)
+}
@@ -0,0 +1,390 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package sops provides configuration sources reading files encrypted using [SOPS], with the data key encrypted for
+// age identities available locally. The files are decrypted into a node structure before they are overlaid, all
+// decrypted values are marked as secret using [templig.SecretTag]:
+//
+//	c, err := templig.New[Config](
+//	    templig.WithFile("my_config.yaml"),
+//	    templig.WithNodeSource(sops.New("secrets.enc.yaml")),
+//	)
+//
+// Each value is authenticated bound to its position in the file. The message authentication code of the whole file is
+// verified as well, so removed, added or changed unencrypted values are detected.
+//
+// [SOPS]: https://getsops.io
+package sops
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"go.yaml.in/yaml/v4"
+
+	"github.com/AlphaOne1/templig"
+)
+
+// metadataKey is the top-level key of the SOPS metadata.
+const metadataKey = "sops"
+
+var (
+	// ErrNoMetadata indicates that a file does not contain SOPS metadata, so it is not encrypted using SOPS.
+	ErrNoMetadata = errors.New("no SOPS metadata found")
+
+	// ErrNoIdentity indicates that none of the given age identities can decrypt the data key.
+	ErrNoIdentity = errors.New("no matching age identity")
+
+	// ErrInvalidValue indicates that an encrypted value is malformed or cannot be decrypted.
+	ErrInvalidValue = errors.New("invalid encrypted value")
+
+	// ErrMACMismatch indicates that the message authentication code of a file does not match its content, so the file
+	// was modified without SOPS.
+	ErrMACMismatch = errors.New("MAC mismatch")
+)
+
+// macOnlyEncryptedInitialization is written first to the message authentication code of files, that only
+// authenticate their encrypted values, so it differs from the one authenticating all values.
+var macOnlyEncryptedInitialization = []byte{
+	0x8a, 0x3f, 0xd2, 0xad, 0x54, 0xce, 0x66, 0x52, 0x7b, 0x10, 0x34, 0xf3, 0xd1, 0x47, 0xbe, 0x0b,
+	0x0b, 0x97, 0x5b, 0x3b, 0xf4, 0x4f, 0x72, 0xc6, 0xfd, 0xad, 0xec, 0x81, 0x76, 0xf2, 0x7d, 0x69,
+}
+
+// encryptedValueRE matches the values encrypted by SOPS.
+var encryptedValueRE = regexp.MustCompile(`^ENC\[AES256_GCM,data:(.*),iv:(.*),tag:(.*),type:(.*)\]$`)
+
+// Source is a configuration source read from a SOPS-encrypted YAML or JSON file. It is used with
+// [templig.WithNodeSource].
+type Source struct {
+	// FileName is the name of the encrypted file.
+	FileName string
+
+	// Identities are the age identities, `AGE-SECRET-KEY-1...`, used to decrypt the data key of the file. If it is
+	// empty, the identities given by [DefaultIdentities] are used.
+	Identities []string
+}
+
+// New creates a source for the given encrypted file, decrypted using the given age identities, or the ones given by
+// [DefaultIdentities] if none are given.
+func New(fileName string, identities ...string) *Source {
+	return &Source{
+		FileName:   fileName,
+		Identities: identities,
+	}
+}
+
+// Name gives the file name of the source.
+func (s *Source) Name() string {
+	return s.FileName
+}
+
+// metadata is the part of the SOPS metadata needed to decrypt the data key and to verify the content.
+type metadata struct {
+	Age []struct {
+		Recipient string `yaml:"recipient"`
+		Enc       string `yaml:"enc"`
+	} `yaml:"age"`
+
+	LastModified     string `yaml:"lastmodified"`
+	MAC              string `yaml:"mac"`
+	MACOnlyEncrypted bool   `yaml:"mac_only_encrypted"`
+}
+
+// Node reads and decrypts the file and verifies its message authentication code. The SOPS metadata is removed, the
+// decrypted values are tagged with [templig.SecretTag].
+func (s *Source) Node() (*yaml.Node, error) {
+	content, err := os.ReadFile(filepath.Clean(s.FileName))
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read %v: %w", s.FileName, err)
+	}
+
+	var doc yaml.Node
+
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("could not parse %v: %w", s.FileName, err)
+	}
+
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, ErrNoMetadata
+	}
+
+	root := doc.Content[0]
+	metaNode, found := removeMetadata(root)
+
+	if !found {
+		return nil, ErrNoMetadata
+	}
+
+	var meta metadata
+
+	if err := metaNode.Decode(&meta); err != nil {
+		return nil, fmt.Errorf("could not parse SOPS metadata: %w", err)
+	}
+
+	dataKey, err := s.dataKey(meta)
+
+	if err != nil {
+		return nil, err
+	}
+
+	d := decrypter{dataKey: dataKey, mac: sha512.New(), macOnlyEncrypted: meta.MACOnlyEncrypted}
+
+	if d.macOnlyEncrypted {
+		_, _ = d.mac.Write(macOnlyEncryptedInitialization)
+	}
+
+	if err := d.decryptNode(root, nil); err != nil {
+		return nil, err
+	}
+
+	if err := d.verify(meta); err != nil {
+		return nil, fmt.Errorf("could not verify %v: %w", s.FileName, err)
+	}
+
+	return &doc, nil
+}
+
+// removeMetadata removes the SOPS metadata from the given mapping and returns it.
+func removeMetadata(root *yaml.Node) (*yaml.Node, bool) {
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == metadataKey {
+			meta := root.Content[i+1]
+			root.Content = append(root.Content[:i], root.Content[i+2:]...)
+
+			return meta, true
+		}
+	}
+
+	return nil, false
+}
+
+// dataKey decrypts the data key of the file using the identities of the source.
+func (s *Source) dataKey(meta metadata) ([]byte, error) {
+	texts := s.Identities
+
+	if len(texts) == 0 {
+		var err error
+
+		if texts, err = DefaultIdentities(); err != nil {
+			return nil, err
+		}
+	}
+
+	identities := make([]age.Identity, 0, len(texts))
+
+	for _, text := range texts {
+		id, err := parseIdentity(text)
+
+		if err != nil {
+			return nil, err
+		}
+
+		identities = append(identities, id)
+	}
+
+	for _, recipient := range meta.Age {
+		key, err := decryptAge(recipient.Enc, identities)
+
+		if errors.Is(err, ErrNoIdentity) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("could not decrypt data key for %v: %w", recipient.Recipient, err)
+		}
+
+		return key, nil
+	}
+
+	return nil, ErrNoIdentity
+}
+
+// decrypter decrypts the values of a file, computing the message authentication code of their plain text on the way.
+type decrypter struct {
+	dataKey          []byte
+	mac              hash.Hash
+	macOnlyEncrypted bool
+}
+
+// decryptNode decrypts all encrypted values of the given node structure. As in SOPS, the path used to authenticate
+// the values consists of the mapping keys only.
+func (d *decrypter) decryptNode(node *yaml.Node, path []string) error {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			removeEncryptedComments(node.Content[i])
+
+			if err := d.decryptNode(node.Content[i+1], append(path, node.Content[i].Value)); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for _, v := range node.Content {
+			if err := d.decryptNode(v, path); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		removeEncryptedComments(node)
+
+		return d.decryptScalar(node, path)
+	default:
+		// aliases are not produced by SOPS
+	}
+
+	return nil
+}
+
+// decryptScalar decrypts the given scalar, if it is encrypted. Decrypted strings are quoted, so they are not
+// resolved to other types.
+func (d *decrypter) decryptScalar(node *yaml.Node, path []string) error {
+	m := encryptedValueRE.FindStringSubmatch(node.Value)
+
+	if m == nil {
+		if !d.macOnlyEncrypted {
+			return d.addPlain(node)
+		}
+
+		return nil
+	}
+
+	plain, err := decryptValue(m[1], m[2], m[3], strings.Join(path, ":")+":", d.dataKey)
+
+	if err != nil {
+		return fmt.Errorf("could not decrypt %v: %w", strings.Join(path, "."), err)
+	}
+
+	// the plain text is the value as written by SOPS, e.g. `True` for booleans
+	_, _ = d.mac.Write([]byte(plain))
+
+	node.Value = plain
+	node.Tag = templig.SecretTag
+	node.Style = 0
+
+	if m[4] == "str" || m[4] == "bytes" {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+
+	return nil
+}
+
+// addPlain adds the given unencrypted scalar to the message authentication code, written as SOPS does.
+func (d *decrypter) addPlain(node *yaml.Node) error {
+	var value any
+
+	if err := node.Decode(&value); err != nil {
+		return fmt.Errorf("could not parse %v: %w", node.Value, err)
+	}
+
+	var text string
+
+	switch v := value.(type) {
+	case bool:
+		text = "False"
+
+		if v {
+			text = "True"
+		}
+	case float64:
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		text = v.Format(time.RFC3339Nano)
+	case nil:
+		text = ""
+	default:
+		text = fmt.Sprint(v)
+	}
+
+	_, _ = d.mac.Write([]byte(text))
+
+	return nil
+}
+
+// verify checks the message authentication code of the file against the one computed while decrypting its values.
+func (d *decrypter) verify(meta metadata) error {
+	m := encryptedValueRE.FindStringSubmatch(meta.MAC)
+
+	if m == nil {
+		return fmt.Errorf("%w: no MAC found", ErrMACMismatch)
+	}
+
+	lastModified, err := time.Parse(time.RFC3339, meta.LastModified)
+
+	if err != nil {
+		return fmt.Errorf("%w: invalid last modification time: %w", ErrMACMismatch, err)
+	}
+
+	want, err := decryptValue(m[1], m[2], m[3], lastModified.Format(time.RFC3339), d.dataKey)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMACMismatch, err)
+	}
+
+	got := strings.ToUpper(hex.EncodeToString(d.mac.Sum(nil)))
+
+	if subtle.ConstantTimeCompare([]byte(got), []byte(want)) != 1 {
+		return ErrMACMismatch
+	}
+
+	return nil
+}
+
+// decryptValue decrypts the given base64-encoded value using AES-256-GCM.
+func decryptValue(data, iv, tag, additionalData string, dataKey []byte) (string, error) {
+	dataBytes, dataErr := base64.StdEncoding.DecodeString(data)
+	ivBytes, ivErr := base64.StdEncoding.DecodeString(iv)
+	tagBytes, tagErr := base64.StdEncoding.DecodeString(tag)
+
+	if err := errors.Join(dataErr, ivErr, tagErr); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidValue, err)
+	}
+
+	block, err := aes.NewCipher(dataKey)
+
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidValue, err)
+	}
+
+	if len(ivBytes) == 0 {
+		return "", fmt.Errorf("%w: missing iv", ErrInvalidValue)
+	}
+
+	aead, err := cipher.NewGCMWithNonceSize(block, len(ivBytes))
+
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidValue, err)
+	}
+
+	plain, err := aead.Open(nil, ivBytes, append(dataBytes, tagBytes...), []byte(additionalData))
+
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidValue, err)
+	}
+
+	return string(plain), nil
+}
+
+// removeEncryptedComments removes the comments encrypted by SOPS from the given node, as they cannot be restored to
+// their original position.
+func removeEncryptedComments(node *yaml.Node) {
+	for _, comment := range []*string{&node.HeadComment, &node.LineComment, &node.FootComment} {
+		if strings.Contains(*comment, "ENC[AES256_GCM,") {
+			*comment = ""
+		}
+	}
+}
@@ -0,0 +1,284 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package sops_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AlphaOne1/templig"
+	"github.com/AlphaOne1/templig/sops"
+)
+
+// unknownIdentity is a valid age identity, that the test files are not encrypted for.
+const unknownIdentity = "AGE-SECRET-KEY-1F9C4NV3ZP9TYDWREPN0W2EU65H2DSFHCZQKN69P4WRCJPN9CH8ESA4QV9X"
+
+type TestConn struct {
+	URL     string   `yaml:"url"`
+	Passes  []string `yaml:"passes"`
+	Enabled bool     `yaml:"enabled"`
+	Port    int      `yaml:"port_unencrypted"`
+}
+
+type TestConfig struct {
+	ID   int      `yaml:"id"`
+	Name string   `yaml:"name"`
+	Conn TestConn `yaml:"conn"`
+}
+
+func identities(t *testing.T, fileName string) []string {
+	t.Helper()
+
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		t.Fatalf("could not open %v: %v", fileName, err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	result, err := sops.ParseIdentities(f)
+
+	if err != nil {
+		t.Fatalf("could not parse identities: %v", err)
+	}
+
+	return result
+}
+
+func TestSource(t *testing.T) {
+	t.Parallel()
+
+	keys := identities(t, "../testData/sops/keys.txt")
+	otherKeys := identities(t, "../testData/sops/other_keys.txt")
+
+	tests := []struct {
+		source    *sops.Source
+		wantErrIs error
+	}{
+		{ // 0
+			source: sops.New("../testData/sops/config.enc.yaml", keys...),
+		},
+		{ // 1
+			source: sops.New("../testData/sops/config.enc.yaml", unknownIdentity, otherKeys[0]),
+		},
+		{ // 2
+			source:    sops.New("../testData/sops/config.enc.yaml", unknownIdentity),
+			wantErrIs: sops.ErrNoIdentity,
+		},
+		{ // 3
+			source:    sops.New("../testData/sops/config.enc.yaml", "AGE-SECRET-KEY-1INVALID"),
+			wantErrIs: sops.ErrInvalidIdentity,
+		},
+		{ // 4
+			source:    sops.New("../testData/sops/moved.enc.yaml", keys...),
+			wantErrIs: sops.ErrInvalidValue,
+		},
+		{ // 5
+			source:    sops.New("../testData/test_config_0.yaml", keys...),
+			wantErrIs: sops.ErrNoMetadata,
+		},
+		{ // 6
+			source:    sops.New("../testData/does_not_exist.enc.yaml", keys...),
+			wantErrIs: fs.ErrNotExist,
+		},
+		{ // 7
+			source: sops.New("../testData/sops/mac_only.enc.yaml", keys...),
+		},
+		{ // 8
+			source:    sops.New("../testData/sops/changed.enc.yaml", keys...),
+			wantErrIs: sops.ErrMACMismatch,
+		},
+		{ // 9
+			source:    sops.New("../testData/sops/removed.enc.yaml", keys...),
+			wantErrIs: sops.ErrMACMismatch,
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("Source-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			config, configErr := templig.New[TestConfig](templig.WithNodeSource(test.source))
+
+			if test.wantErrIs != nil {
+				if !errors.Is(configErr, test.wantErrIs) {
+					t.Errorf("%v: wanted error %v but got %v", testIndex, test.wantErrIs, configErr)
+				}
+
+				return
+			}
+
+			if configErr != nil {
+				t.Fatalf("%v: did not want error but got %v", testIndex, configErr)
+			}
+
+			want := TestConfig{
+				ID:   23,
+				Name: "Interesting Name",
+				Conn: TestConn{
+					URL:     "postgres://db:5432",
+					Passes:  []string{"secretPass0", "1234"},
+					Enabled: true,
+					Port:    5432,
+				},
+			}
+
+			got := config.Get()
+
+			if fmt.Sprint(*got) != fmt.Sprint(want) {
+				t.Errorf("%v: wanted %v but got %v", testIndex, want, *got)
+			}
+		})
+	}
+}
+
+func TestSourceTypes(t *testing.T) {
+	t.Parallel()
+
+	config, configErr := templig.New[map[string]any](
+		templig.WithNodeSource(sops.New("../testData/sops/config.enc.yaml", identities(t, "../testData/sops/keys.txt")...)))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	got := *config.Get()
+	conn, _ := got["conn"].(map[string]any)
+	passes, _ := conn["passes"].([]any)
+
+	if _, found := got["sops"]; found {
+		t.Errorf("wanted metadata removed but got %v", got["sops"])
+	}
+
+	if got["id"] != 23 || conn["enabled"] != true || len(passes) != 2 || passes[1] != "1234" {
+		t.Errorf("wanted values of their encrypted types but got %#v", got)
+	}
+}
+
+func TestSourceHidden(t *testing.T) {
+	t.Parallel()
+
+	config, configErr := templig.New[TestConfig](
+		templig.WithNodeSource(sops.New("../testData/sops/config.enc.yaml", identities(t, "../testData/sops/keys.txt")...)))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	var b bytes.Buffer
+
+	if err := config.ToSecretsHiddenStructured(&b); err != nil {
+		t.Fatalf("did not want error but got %v", err)
+	}
+
+	for _, secret := range []string{"Interesting Name", "postgres://db:5432", "secretPass0", "1234", "23"} {
+		if strings.Contains(b.String(), secret) {
+			t.Errorf("wanted %v to be hidden but got %v", secret, b.String())
+		}
+	}
+
+	if !strings.Contains(b.String(), "port_unencrypted: 5432") {
+		t.Errorf("wanted unencrypted values visible but got %v", b.String())
+	}
+
+	if origin, found := config.Origin("conn.url"); !found || !origin.Secret {
+		t.Errorf("wanted origin of conn.url to be secret but got %v", origin)
+	}
+}
+
+//nolint:paralleltest // modifies the environment
+func TestDefaultIdentities(t *testing.T) {
+	keys := identities(t, "../testData/sops/keys.txt")
+	configDir := t.TempDir()
+
+	keyDir := filepath.Join(configDir, "sops", "age")
+
+	if err := os.MkdirAll(keyDir, 0o700); err != nil {
+		t.Fatalf("could not create directory: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(keyDir, "keys.txt"), []byte(unknownIdentity), 0o600); err != nil {
+		t.Fatalf("could not write keys: %v", err)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("HOME", configDir)
+	t.Setenv("SOPS_AGE_KEY", keys[0])
+	t.Setenv("SOPS_AGE_KEY_FILE", "../testData/sops/other_keys.txt")
+
+	got, err := sops.DefaultIdentities()
+
+	if err != nil {
+		t.Fatalf("did not want error but got %v", err)
+	}
+
+	if len(got) != 3 || got[0] != keys[0] || got[2] != unknownIdentity {
+		t.Errorf("wanted identities from environment, key file and configuration directory but got %v", got)
+	}
+
+	if _, err := templig.New[TestConfig](
+		templig.WithNodeSource(sops.New("../testData/sops/config.enc.yaml"))); err != nil {
+		t.Errorf("did not want error but got %v", err)
+	}
+
+	t.Setenv("SOPS_AGE_KEY", "no identity")
+
+	if _, err := sops.DefaultIdentities(); !errors.Is(err, sops.ErrInvalidIdentity) {
+		t.Errorf("wanted error %v but got %v", sops.ErrInvalidIdentity, err)
+	}
+}
+
+func TestParseIdentities(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in        string
+		wantCount int
+		wantErrIs error
+	}{
+		{ // 0
+			in:        "# comment\n\n" + unknownIdentity + "\n",
+			wantCount: 1,
+		},
+		{ // 1
+			in:        strings.ToLower(unknownIdentity),
+			wantErrIs: sops.ErrInvalidIdentity,
+		},
+		{ // 2
+			in:        unknownIdentity[:len(unknownIdentity)-1] + "Q",
+			wantErrIs: sops.ErrInvalidIdentity,
+		},
+		{ // 3
+			in:        "age1v7x3plldpk6nyuvf894gmv7m305ceu0jkg0fy2tnnwt58ztrkf4qveduap",
+			wantErrIs: sops.ErrInvalidIdentity,
+		},
+		{ // 4
+			in:        "AGE-SECRET-KEY-1" + strings.ToLower(unknownIdentity[16:]),
+			wantErrIs: sops.ErrInvalidIdentity,
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("ParseIdentities-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			got, err := sops.ParseIdentities(strings.NewReader(test.in))
+
+			if !errors.Is(err, test.wantErrIs) {
+				t.Errorf("%v: wanted error %v but got %v", testIndex, test.wantErrIs, err)
+			}
+
+			if len(got) != test.wantCount {
+				t.Errorf("%v: wanted %v identities but got %v", testIndex, test.wantCount, got)
+			}
+		})
+	}
+}
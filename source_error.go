@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v4"
+)
+
+// SourceError is an error located in a configuration source, e.g. a template or YAML syntax error.
+type SourceError struct {
+	// Source is the name of the source, that is the file name for files.
+	Source string
+	// Line is the line of the error in the source, zero if unknown. For YAML errors, it is the line after templating.
+	Line int
+	// Column is the column of the error in the source, zero if unknown.
+	Column int
+	// Snippet is the content of the offending line, empty if unknown. For YAML errors, it is the line of the template
+	// source, so that values inserted by templates, e.g. secrets, do not show up in error messages.
+	Snippet string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error gives the error message in the common `source:line:column: message` notation, followed by the snippet.
+func (e *SourceError) Error() string {
+	var b strings.Builder
+
+	b.WriteString(e.Source)
+
+	if e.Line > 0 {
+		b.WriteString(":" + strconv.Itoa(e.Line))
+
+		if e.Column > 0 {
+			b.WriteString(":" + strconv.Itoa(e.Column))
+		}
+	}
+
+	b.WriteString(": " + e.Err.Error())
+
+	if e.Snippet != "" {
+		fmt.Fprintf(&b, ", near %q", e.Snippet)
+	}
+
+	return b.String()
+}
+
+// Unwrap gives the underlying error.
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// templateErrorPositionRE matches the position information in errors of the templating engine, that has the form
+// `template: name:line:column:` with the column being optional.
+var templateErrorPositionRE = regexp.MustCompile(`template: .*?:(\d+)(?::(\d+))?: `)
+
+// templateSourceError wraps an error of the templating engine into a SourceError, giving the position contained in
+// the error message and the offending line of the given content.
+func templateSourceError(name string, content []byte, err error) error {
+	result := &SourceError{Source: name, Err: err}
+
+	if m := templateErrorPositionRE.FindStringSubmatch(err.Error()); m != nil {
+		result.Line, _ = strconv.Atoi(m[1])
+		result.Column, _ = strconv.Atoi(m[2])
+		result.Snippet = snippet(content, result.Line)
+	}
+
+	return result
+}
+
+// yamlSourceError wraps an error of the YAML library into a SourceError, giving the position contained in the error
+// and the offending line of the template source the rendered content was produced from.
+func yamlSourceError(name string, source, rendered []byte, err error) error {
+	result := &SourceError{Source: name, Err: err}
+
+	if loadErr := (*yaml.LoadError)(nil); errors.As(err, &loadErr) {
+		result.Line = loadErr.Mark.Line
+		result.Column = loadErr.Mark.Column
+		result.Snippet = sourceSnippet(source, rendered, result.Line)
+	}
+
+	return result
+}
+
+// mergedSourceError wraps an error decoding the configuration into a SourceError, giving the offending line of the
+// template source. The source is determined using the recorded origins of the values, defaulting to the given name if
+// no value is at the position of the error. If the source is not unambiguously determinable, the error is returned
+// unchanged.
+func mergedSourceError(origins map[string]Origin, texts sourceTexts, name string, err error) error {
+	loadErr := (*yaml.LoadError)(nil)
+
+	if !errors.As(err, &loadErr) || loadErr.Mark.Line == 0 {
+		if name == "" {
+			return err
+		}
+
+		return &SourceError{Source: name, Err: err}
+	}
+
+	found := ""
+
+	for _, o := range origins {
+		if o.Line != loadErr.Mark.Line || o.Column != loadErr.Mark.Column {
+			continue
+		}
+
+		if found != "" && found != o.Source {
+			return err
+		}
+
+		found = o.Source
+	}
+
+	if found != "" {
+		name = found
+	}
+
+	if name == "" {
+		return err
+	}
+
+	return &SourceError{
+		Source:  name,
+		Line:    loadErr.Mark.Line,
+		Column:  loadErr.Mark.Column,
+		Snippet: snippet(texts[name], loadErr.Mark.Line),
+		Err:     err,
+	}
+}
+
+// sourceTexts holds the template sources of the loaded sources by their name, to give the offending line of errors
+// decoding the configuration. It is shared with the configurations created during overlays.
+type sourceTexts map[string][]byte
+
+// withSourceTexts creates an Option that sets the template sources recorded while reading. It is used to share them
+// with the configurations created during overlays.
+func withSourceTexts(texts sourceTexts) Option {
+	return func(c configurable) error {
+		return c.setSourceTexts(texts)
+	}
+}
+
+func (c *Config[T]) setSourceTexts(texts sourceTexts) error {
+	c.texts = texts
+
+	return nil
+}
+
+// add records the template source of the named source. Sources whose lines do not correspond to the lines of their
+// rendered content are not recorded, as the position of an error cannot be attributed to their lines.
+func (t sourceTexts) add(name string, source, rendered []byte) {
+	if t != nil && linesCorrespond(source, rendered) {
+		t[name] = source
+	}
+}
+
+// snippet gives the given line of the content, with surrounding whitespace removed. Lines beyond the end of the
+// content, as reported for errors at its end, give the last line.
+func snippet(content []byte, line int) string {
+	lines := bytes.Split(bytes.TrimRight(content, "\n"), []byte("\n"))
+
+	if line <= 0 || len(content) == 0 {
+		return ""
+	}
+
+	line = min(line, len(lines))
+
+	return string(bytes.TrimSpace(lines[line-1]))
+}
+
+// sourceSnippet gives the line of the template source corresponding to the given line of the rendered content. As
+// templates may produce multiple lines, the lines only correspond if both have the same number of lines, otherwise no
+// snippet is given. The rendered content itself is never used, as it may contain secrets inserted by the templates.
+func sourceSnippet(source, rendered []byte, line int) string {
+	if !linesCorrespond(source, rendered) {
+		return ""
+	}
+
+	return snippet(source, line)
+}
+
+// linesCorrespond checks if the template source and the rendered content have the same number of lines.
+func linesCorrespond(source, rendered []byte) bool {
+	return bytes.Count(bytes.TrimRight(source, "\n"), []byte("\n")) ==
+		bytes.Count(bytes.TrimRight(rendered, "\n"), []byte("\n"))
+}
@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package templig_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AlphaOne1/templig"
+)
+
+func TestSourceError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		options     []templig.Option
+		wantSource  string
+		wantLine    int
+		wantColumn  int
+		wantSnippet string
+	}{
+		{ // 0
+			options: []templig.Option{
+				templig.WithNamedReader("named", strings.NewReader("id: 9\nname: {{ required \"name required\" \"\" }}")),
+			},
+			wantSource:  "named",
+			wantLine:    2,
+			wantColumn:  9,
+			wantSnippet: `name: {{ required "name required" "" }}`,
+		},
+		{ // 1
+			options: []templig.Option{
+				templig.WithReader(strings.NewReader("id: 9")),
+				templig.WithReader(strings.NewReader("id: 9\n\nname: {{ quote ")),
+			},
+			wantSource:  "reader[1]",
+			wantLine:    3,
+			wantSnippet: "name: {{ quote",
+		},
+		{ // 2
+			options: []templig.Option{
+				templig.WithFile("testData/test_config_2.yaml"),
+			},
+			wantSource:  "testData/test_config_2.yaml",
+			wantLine:    9,
+			wantColumn:  41,
+			wantSnippet: `- {{ read "secret_not_found.txt" | required "secret.txt must be readable" | quote }}`,
+		},
+		{ // 3
+			options: []templig.Option{
+				templig.WithNamedReader("yaml", strings.NewReader("id: 9\nname: {{ \"[a\" }}")),
+			},
+			wantSource:  "yaml",
+			wantLine:    3,
+			wantColumn:  1,
+			wantSnippet: `name: {{ "[a" }}`,
+		},
+		{ // 4
+			options: []templig.Option{
+				templig.WithNamedReader("typed", strings.NewReader("id: 9\nname: [a]")),
+			},
+			wantSource:  "typed",
+			wantLine:    2,
+			wantColumn:  7,
+			wantSnippet: "name: [a]",
+		},
+		{ // 5
+			options: []templig.Option{
+				templig.WithFile("testData/test_config_0.yaml"),
+				templig.WithFile("testData/test_config_0_overlay_wrongtype.yaml"),
+			},
+			wantSource:  "testData/test_config_0_overlay_wrongtype.yaml",
+			wantLine:    4,
+			wantColumn:  5,
+			wantSnippet: "id: Invalid",
+		},
+		{ // 6
+			options: []templig.Option{
+				templig.WithNamedReader("single", strings.NewReader("name: n\nid: {{ \"abc\" }}")),
+			},
+			wantSource:  "single",
+			wantLine:    2,
+			wantColumn:  5,
+			wantSnippet: `id: {{ "abc" }}`,
+		},
+		{ // 7
+			options: []templig.Option{
+				templig.WithNamedReader("base", strings.NewReader("id: 9")),
+				templig.WithNamedReader("overlay", strings.NewReader("name: n\nid: {{ \"abc\" }}")),
+			},
+			wantSource:  "overlay",
+			wantLine:    2,
+			wantColumn:  5,
+			wantSnippet: `id: {{ "abc" }}`,
+		},
+		{ // 8
+			options: []templig.Option{
+				templig.WithNamedReader("base", strings.NewReader("id: 9")),
+				templig.WithNamedReader("lines", strings.NewReader("{{ print \"name: n\\nid: abc\" }}")),
+			},
+			wantSource: "lines",
+			wantLine:   2,
+			wantColumn: 5,
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("SourceError-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			_, configErr := templig.New[TestConfig](test.options...)
+
+			var sourceErr *templig.SourceError
+
+			if !errors.As(configErr, &sourceErr) {
+				t.Fatalf("%v: wanted source error but got %v", testIndex, configErr)
+			}
+
+			if sourceErr.Source != test.wantSource {
+				t.Errorf("%v: wanted source %v but got %v", testIndex, test.wantSource, sourceErr.Source)
+			}
+
+			if sourceErr.Line != test.wantLine || sourceErr.Column != test.wantColumn {
+				t.Errorf("%v: wanted position %v:%v but got %v:%v",
+					testIndex, test.wantLine, test.wantColumn, sourceErr.Line, sourceErr.Column)
+			}
+
+			if sourceErr.Snippet != test.wantSnippet {
+				t.Errorf("%v: wanted snippet %q but got %q", testIndex, test.wantSnippet, sourceErr.Snippet)
+			}
+
+			if !strings.HasPrefix(configErr.Error(), test.wantSource+":") {
+				t.Errorf("%v: wanted error message to start with the source but got %v", testIndex, configErr)
+			}
+		})
+	}
+}
+
+func TestSourceErrorHidesRendered(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in          string
+		wantSnippet string
+	}{
+		{ // 0
+			in:          "id: 9\npassword: {{ env \"DB_PASS\" }}",
+			wantSnippet: `password: {{ env "DB_PASS" }}`,
+		},
+		{ // 1
+			in: "{{ print \"id: 9\\nname: n\" }}\npassword: {{ env \"DB_PASS\" }}",
+		},
+	}
+
+	for testIndex, test := range tests {
+		t.Run(fmt.Sprintf("SourceErrorHidesRendered-%d", testIndex), func(t *testing.T) {
+			t.Parallel()
+
+			_, configErr := templig.New[TestConfig](
+				templig.WithEnv(map[string]string{"DB_PASS": "s3cr3t: x"}),
+				templig.WithReader(strings.NewReader(test.in)))
+
+			var sourceErr *templig.SourceError
+
+			if !errors.As(configErr, &sourceErr) {
+				t.Fatalf("%v: wanted source error but got %v", testIndex, configErr)
+			}
+
+			if strings.Contains(configErr.Error(), "s3cr3t") {
+				t.Errorf("%v: wanted secret not to be part of the error but got %v", testIndex, configErr)
+			}
+
+			if sourceErr.Snippet != test.wantSnippet {
+				t.Errorf("%v: wanted snippet %q but got %q", testIndex, test.wantSnippet, sourceErr.Snippet)
+			}
+		})
+	}
+}
+
+func TestSourceErrorMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err  templig.SourceError
+		want string
+	}{
+		{ // 0
+			err:  templig.SourceError{Source: "a.yaml", Err: errors.New("failed")},
+			want: "a.yaml: failed",
+		},
+		{ // 1
+			err:  templig.SourceError{Source: "a.yaml", Line: 3, Err: errors.New("failed")},
+			want: "a.yaml:3: failed",
+		},
+		{ // 2
+			err:  templig.SourceError{Source: "a.yaml", Line: 3, Column: 4, Snippet: "a: b", Err: errors.New("failed")},
+			want: `a.yaml:3:4: failed, near "a: b"`,
+		},
+	}
+
+	for testIndex, test := range tests {
+		if got := test.err.Error(); got != test.want {
+			t.Errorf("%v: wanted %v but got %v", testIndex, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package sqlsource_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+)
+
+var errFakeQuery = errors.New("fake query error")
+
+// fakeRow is a row of the settings table of the fake driver.
+type fakeRow struct {
+	tenant string
+	key    string
+	value  any
+}
+
+// fakeTables holds the settings tables of the fake driver by their data source name.
+var fakeTables sync.Map //nolint:gochecknoglobals
+
+func init() { //nolint:gochecknoinits
+	sql.Register("templig-fake", fakeDriver{})
+}
+
+// openFake opens a fake database with the given settings table.
+func openFake(name string, rows []fakeRow) (*sql.DB, error) {
+	fakeTables.Store(name, rows)
+
+	return sql.Open("templig-fake", name)
+}
+
+// fakeDriver is a database driver only supporting queries of its settings table. The query text `fail` gives an
+// error, a query argument filters the rows by tenant.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	rows, _ := fakeTables.Load(name)
+
+	return fakeConn{rows: rows.([]fakeRow)}, nil //nolint:forcetypeassert
+}
+
+type fakeConn struct {
+	rows []fakeRow
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	if query == "fail" {
+		return nil, errFakeQuery
+	}
+
+	return fakeStmt(c), nil
+}
+
+func (fakeConn) Close() error {
+	return nil
+}
+
+func (fakeConn) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+type fakeStmt struct {
+	rows []fakeRow
+}
+
+func (fakeStmt) Close() error {
+	return nil
+}
+
+func (fakeStmt) NumInput() int {
+	return -1
+}
+
+func (fakeStmt) Exec(_ []driver.Value) (driver.Result, error) {
+	return nil, driver.ErrSkip
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	result := &fakeRows{}
+
+	for _, row := range s.rows {
+		if len(args) == 0 || args[0] == row.tenant {
+			result.rows = append(result.rows, row)
+		}
+	}
+
+	return result, nil
+}
+
+type fakeRows struct {
+	rows []fakeRow
+}
+
+func (*fakeRows) Columns() []string {
+	return []string{"key", "value"}
+}
+
+func (*fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	dest[0], dest[1] = r.rows[0].key, r.rows[0].value
+	r.rows = r.rows[1:]
+
+	return nil
+}
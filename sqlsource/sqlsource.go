@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package sqlsource provides configuration sources reading settings from SQL databases using database/sql. The
+// database driver is chosen by the application, so templig does not depend on any.
+//
+// The rows of a query giving keys and values, e.g. from a `settings(key, value)` table, become a configuration tree,
+// the dotted keys becoming its levels:
+//
+//	c, err := templig.New[Config](
+//	    templig.WithFile("my_config.yaml"),
+//	    templig.WithNodeSource(sqlsource.New(db, "SELECT key, value FROM settings WHERE tenant = ?", tenant)),
+//	)
+package sqlsource
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/AlphaOne1/templig"
+)
+
+// DefaultSeparator is the separator of key segments used by sources created using [New].
+const DefaultSeparator = "."
+
+// Source is a configuration source built from the rows of an SQL query. The query has to give two columns, the key
+// and the value of a setting. Rows with NULL values are left out. It is used with [templig.WithNodeSource].
+type Source struct {
+	// DB is the database to query.
+	DB *sql.DB
+
+	// Query is the query giving the keys and values.
+	Query string
+
+	// Args are the arguments of the query.
+	Args []any
+
+	// Separator separates the segments of the keys, that become the levels of the configuration.
+	Separator string
+
+	// Label is the name of the source, used e.g. to record the origin of values. If it is empty, `sql` is used.
+	Label string
+
+	// Timeout is the maximum duration of the query. Zero means no limit.
+	Timeout time.Duration
+}
+
+// New creates a source for the settings given by the query, using the [DefaultSeparator].
+func New(db *sql.DB, query string, args ...any) *Source {
+	return &Source{
+		DB:        db,
+		Query:     query,
+		Args:      args,
+		Separator: DefaultSeparator,
+	}
+}
+
+// Name gives the name of the source, used e.g. to record the origin of values.
+func (s *Source) Name() string {
+	if s.Label != "" {
+		return s.Label
+	}
+
+	return "sql"
+}
+
+// Node gives the current content of the source.
+func (s *Source) Node() (*yaml.Node, error) {
+	values, err := s.settings()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not query %v: %w", s.Name(), err)
+	}
+
+	node, nodeErr := templig.NodeFromKeys(values, s.Separator)
+
+	if nodeErr != nil {
+		return nil, fmt.Errorf("could not map %v: %w", s.Name(), nodeErr)
+	}
+
+	return node, nil
+}
+
+// settings gives the keys and values of the settings given by the query.
+func (s *Source) settings() (map[string]string, error) {
+	ctx := context.Background()
+
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	rows, err := s.DB.QueryContext(ctx, s.Query, s.Args...)
+
+	if err != nil {
+		return nil, err //nolint:wrapcheck // wrapped by the caller
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	result := make(map[string]string)
+
+	for rows.Next() {
+		var key string
+		var value sql.NullString
+
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err //nolint:wrapcheck // wrapped by the caller
+		}
+
+		if value.Valid {
+			result[key] = value.String
+		}
+	}
+
+	return result, rows.Err() //nolint:wrapcheck // wrapped by the caller
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2026 The templig contributors.
+// SPDX-License-Identifier: MPL-2.0
+
+package sqlsource_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AlphaOne1/templig"
+	"github.com/AlphaOne1/templig/sqlsource"
+)
+
+type TestConn struct {
+	URL    string   `yaml:"url"`
+	Passes []string `yaml:"passes"`
+}
+
+type TestConfig struct {
+	ID   int       `yaml:"id"`
+	Name string    `yaml:"name"`
+	Conn *TestConn `yaml:"conn,omitempty"`
+}
+
+func TestSource(t *testing.T) {
+	t.Parallel()
+
+	db, dbErr := openFake("TestSource", []fakeRow{
+		{tenant: "a", key: "id", value: "9"},
+		{tenant: "a", key: "name", value: "sqlName"},
+		{tenant: "a", key: "conn.passes.0", value: "pass0"},
+		{tenant: "a", key: "conn.url", value: nil},
+		{tenant: "b", key: "name", value: "otherName"},
+	})
+
+	if dbErr != nil {
+		t.Fatalf("could not open database: %v", dbErr)
+	}
+
+	t.Cleanup(func() { _ = db.Close() })
+
+	config, configErr := templig.New[TestConfig](
+		templig.WithReader(strings.NewReader("id: 1\nname: readerName\nconn:\n  url: https://www.tests.to")),
+		templig.WithNodeSource(sqlsource.New(db, "SELECT key, value FROM settings WHERE tenant = ?", "a")))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	if config.Get().ID != 9 || config.Get().Name != "sqlName" {
+		t.Errorf("wanted id 9 and name sqlName but got %v and %v", config.Get().ID, config.Get().Name)
+	}
+
+	if config.Get().Conn.URL != "https://www.tests.to" || len(config.Get().Conn.Passes) != 1 {
+		t.Errorf("wanted url https://www.tests.to and 1 pass but got %v", config.Get().Conn)
+	}
+
+	if origin, _ := config.Origin("name"); origin.Source != "sql" {
+		t.Errorf("wanted origin sql but got %v", origin)
+	}
+}
+
+func TestSourceLabel(t *testing.T) {
+	t.Parallel()
+
+	db, dbErr := openFake("TestSourceLabel", []fakeRow{{key: "id", value: "9"}})
+
+	if dbErr != nil {
+		t.Fatalf("could not open database: %v", dbErr)
+	}
+
+	t.Cleanup(func() { _ = db.Close() })
+
+	source := sqlsource.New(db, "SELECT key, value FROM settings")
+	source.Label = "settings"
+
+	config, configErr := templig.New[TestConfig](templig.WithNodeSource(source))
+
+	if configErr != nil {
+		t.Fatalf("did not want error but got %v", configErr)
+	}
+
+	if origin, _ := config.Origin("id"); origin.Source != "settings" {
+		t.Errorf("wanted origin settings but got %v", origin)
+	}
+}
+
+func TestSourceErrors(t *testing.T) {
+	t.Parallel()
+
+	db, dbErr := openFake("TestSourceErrors", []fakeRow{
+		{key: "conn", value: "x"},
+		{key: "conn.url", value: "y"},
+	})
+
+	if dbErr != nil {
+		t.Fatalf("could not open database: %v", dbErr)
+	}
+
+	t.Cleanup(func() { _ = db.Close() })
+
+	if _, err := templig.New[TestConfig](
+		templig.WithNodeSource(sqlsource.New(db, "fail"))); !errors.Is(err, errFakeQuery) {
+		t.Errorf("wanted error %v but got %v", errFakeQuery, err)
+	}
+
+	conflicting := sqlsource.New(db, "SELECT key, value FROM settings")
+
+	if _, err := templig.New[TestConfig](templig.WithNodeSource(conflicting)); !errors.Is(err, templig.ErrKeyConflict) {
+		t.Errorf("wanted error %v but got %v", templig.ErrKeyConflict, err)
+	}
+}